@@ -0,0 +1,277 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Accessible mode is for screen readers. Instead of redrawing a boxed layout
+// on the alt screen, each tab is printed once as linear, labeled plain text
+// into the normal scrollback, and the only live line is a short status
+// prompt. Nothing is conveyed by color, borders or decorative glyphs alone.
+//
+// Visitors get it by connecting as accessibleUser (ssh a11y@host), by
+// sending accessibleEnv, or by pressing the Accessible key.
+const (
+	accessibleUser = "a11y"
+	accessibleEnv  = "ACCESSIBLE"
+)
+
+// wantsAccessible reports whether a session asked for accessible mode.
+func wantsAccessible(user string, environ []string) bool {
+	if user == accessibleUser {
+		return true
+	}
+	for _, kv := range environ {
+		if v, ok := strings.CutPrefix(kv, accessibleEnv+"="); ok && v != "" && v != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// plainPeriod reads a date range as words rather than a dash.
+func plainPeriod(l *locale, period string) string {
+	return strings.ReplaceAll(l.words(period), " — ", " "+l.T("to")+" ")
+}
+
+// items counts list items for a label, so a listener knows how long a list
+// is before it starts.
+func items(l *locale, n int) string {
+	if n == 1 {
+		return l.T("1 item")
+	}
+	return l.F("%d items", n)
+}
+
+// plainTab is the announcement for tab i: a heading line naming the tab and
+// its position, then its content.
+func (m model) plainTab(i int) string {
+	l := m.locale
+	var body string
+	switch t := m.tabs[i]; t.Type {
+	case sectionBio:
+		body = plainAbout(l, t.profile(m.portfolio))
+	case sectionTimeline:
+		if t.builtin() {
+			body = plainExperience(l, m.portfolio)
+		} else {
+			body = plainEntries(l, t.Entries)
+		}
+	case sectionCards:
+		body = plainProjects(l, t.cards(m.portfolio))
+	case sectionTags:
+		if t.builtin() {
+			body = plainSkills(m.portfolio.skillGroups)
+		} else {
+			body = plainSkills(t.Groups)
+		}
+	case sectionList:
+		body = plainContact(l, t.contacts(m.portfolio))
+		if h := m.contactHint(); h != "" && t.builtin() {
+			body += "\n" + h + "\n"
+		}
+	case sectionMarkdown:
+		body = plainMarkdown(t.Body)
+	case sectionPosts:
+		body = plainWriting(l, m.filteredPosts(), m.keys.NextItem.Help().Key)
+	case sectionGuestbook:
+		body = plainGuestbook(l, m.guestbookEntries(), m.guestCursor, m.signHint())
+	case sectionAdmin:
+		body = plainEdits(l, m.edit.targets, m.editHint())
+	}
+	heading := l.F("Tab %d of %d: %s", i+1, len(m.tabs), l.T(m.tabs[i].Title))
+	return "\n" + heading + "\n\n" + strings.TrimRight(body, "\n") + "\n"
+}
+
+func plainAbout(l *locale, profile Profile) string {
+	var b strings.Builder
+	b.WriteString(l.F("Name: %s", profile.Name) + "\n")
+	b.WriteString(l.F("Role: %s", profile.Role) + "\n")
+	b.WriteString(l.F("Location: %s", profile.Location) + "\n\n")
+	b.WriteString(plainMarkdown(profile.Bio) + "\n")
+	return b.String()
+}
+
+func plainExperience(l *locale, p portfolio) string {
+	var b strings.Builder
+	exps := p.sortedExperiences()
+	for i, exp := range exps {
+		b.WriteString(l.F("Role %d of %d: %s at %s.", i+1, len(exps), exp.Title, exp.Company) + "\n")
+		dates := l.F("Dates: %s", plainPeriod(l, exp.PeriodText()))
+		if t := exp.Tenure(); t != "" {
+			dates += ", " + l.words(t)
+		}
+		b.WriteString(dates + ".\n")
+		b.WriteString(plainMarkdown(exp.Description) + "\n")
+		b.WriteString(l.F("Highlights, %s:", items(l, len(exp.Highlights))) + "\n")
+		for _, h := range exp.Highlights {
+			b.WriteString("- " + h + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func plainProjects(l *locale, projects []Project) string {
+	var b strings.Builder
+	for i, p := range projects {
+		b.WriteString(l.F("Project %d of %d: %s", i+1, len(projects), p.Name) + "\n")
+		b.WriteString(plainMarkdown(p.Description) + "\n")
+		b.WriteString(l.F("Built with: %s", strings.Join(p.Tech, ", ")) + "\n")
+		if u := p.link(); u != "" {
+			b.WriteString(l.F("Link: %s", u) + "\n")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func plainSkills(groups []SkillGroup) string {
+	var b strings.Builder
+	for _, group := range groups {
+		fmt.Fprintf(&b, "%s: %s.\n", group.Category, strings.Join(group.Skills, ", "))
+	}
+	return b.String()
+}
+
+// plainSkill announces the skill at index i of p's allSkills and where it
+// was used.
+func plainSkill(l *locale, p portfolio, i int) string {
+	skills := p.allSkills()
+	if i >= len(skills) {
+		return ""
+	}
+	skill := skills[i]
+	ev := p.evidenceFor(skill)
+
+	var b strings.Builder
+	b.WriteString("\n" + l.F("Skill %d of %d: %s", i+1, len(skills), skill) + "\n")
+	if ev.empty() {
+		b.WriteString(l.T("No linked experience or projects yet.") + "\n")
+		return b.String()
+	}
+	if len(ev.Highlights) > 0 {
+		b.WriteString(l.F("Used in experience, %s:", items(l, len(ev.Highlights))) + "\n")
+		for _, ref := range ev.Highlights {
+			b.WriteString("- " + l.F("%s at %s: %s", ref.Experience.Title, ref.Experience.Company, ref.Highlight) + "\n")
+		}
+	}
+	if len(ev.Projects) > 0 {
+		names := make([]string, len(ev.Projects))
+		for i, p := range ev.Projects {
+			names[i] = p.Name
+		}
+		b.WriteString(l.F("Used in projects: %s.", strings.Join(names, ", ")) + "\n")
+	}
+	return b.String()
+}
+
+func plainContact(l *locale, contacts []ContactInfo) string {
+	var b strings.Builder
+	for _, c := range contacts {
+		value := c.Value
+		if c.Label == "Portfolio" {
+			value = l.F("https://%s, or over SSH at %s, where you are now", c.Value, c.Value)
+		} else if u := c.webURL(); u != "" {
+			value = u
+		}
+		fmt.Fprintf(&b, "%s: %s\n", l.T(c.Label), value)
+	}
+	return b.String()
+}
+
+// plainHelp lists every enabled key binding, one per line.
+func (m model) plainHelp() string {
+	var b strings.Builder
+	b.WriteString("\n" + m.locale.T("Keys:") + "\n")
+	for _, col := range m.keys.FullHelp() {
+		for _, k := range col {
+			if k.Enabled() && k.Help().Key != "" {
+				fmt.Fprintf(&b, "%s: %s\n", k.Help().Key, m.locale.T(k.Help().Desc))
+			}
+		}
+	}
+	return b.String()
+}
+
+// plainStatus is the accessible mode's only live line: where the visitor is
+// and the keys that matter most.
+func (m model) plainStatus() string {
+	l := m.locale
+	if m.overlay == signOverlay {
+		return l.F("Message, %s to sign, %s to cancel: %s", m.keys.Select.Help().Key, m.keys.Close.Help().Key, m.signInput.View())
+	}
+	if m.overlay == contactOverlay {
+		f := m.contact
+		views := [contactFields]string{f.name.View(), f.replyTo.View(), f.message.View()}
+		return l.F("%s, %s for the next field, %s to send, %s to cancel: %s", l.T(contactLabels[f.focus]),
+			m.keys.NextField.Help().Key, m.keys.Send.Help().Key, m.keys.Close.Help().Key, views[f.focus])
+	}
+	var hints []string
+	for _, k := range []key.Binding{m.keys.Next, m.keys.Prev, m.keys.NextItem, m.keys.Sign, m.keys.Compose, m.keys.Help, m.keys.Quit} {
+		if k.Enabled() {
+			hints = append(hints, k.Help().Key+" "+l.T(k.Help().Desc))
+		}
+	}
+	return l.F("%s, tab %d of %d. Keys: %s.", l.T(m.tabs[m.activeTab].Title), m.activeTab+1, len(m.tabs), strings.Join(hints, ", "))
+}
+
+// updateAccessible handles keys in accessible mode, printing what changed
+// instead of redrawing it.
+func (m model) updateAccessible(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Next):
+		m = m.setTab((m.activeTab + 1) % len(m.tabs))
+	case key.Matches(msg, m.keys.Prev):
+		m = m.setTab((m.activeTab - 1 + len(m.tabs)) % len(m.tabs))
+	case key.Matches(msg, m.keys.Jump):
+		i := int(msg.String()[0] - '1')
+		if i >= len(m.tabs) {
+			return m, nil
+		}
+		m = m.setTab(i)
+
+	case key.Matches(msg, m.keys.NextItem, m.keys.PrevItem):
+		m = m.stepItem(key.Matches(msg, m.keys.NextItem))
+		switch m.tabs[m.activeTab].Type {
+		case sectionPosts:
+			return m, tea.Println(plainPost(m.locale, m.filteredPosts(), m.postCursor))
+		case sectionGuestbook:
+			return m, tea.Println("\n" + plainGuestbook(m.locale, m.guestbookEntries(), m.guestCursor, m.signHint()))
+		}
+		return m, tea.Println(plainSkill(m.locale, m.portfolio, m.skillCursor))
+
+	case key.Matches(msg, m.keys.Help):
+		return m, tea.Println(m.plainHelp())
+
+	case key.Matches(msg, m.keys.Sign):
+		return m.openSignForm(), nil
+
+	case key.Matches(msg, m.keys.Compose):
+		m = m.openContactForm()
+		return m, m.announceField()
+
+	case key.Matches(msg, m.keys.Keymap):
+		m.keys = keyMapFor(nextKeymap(m.keys.Name), len(m.tabs))
+		m.syncKeys()
+		m.visitor.prefs.Keymap = m.keys.Name
+		return m, tea.Batch(tea.Println("\n"+m.locale.F("Key bindings: %s.", m.keys.Name)), m.visitor.savePrefs())
+
+	case key.Matches(msg, m.keys.Language):
+		m = m.setLocale(nextLocale(m.app.locales, m.locale))
+		return m, tea.Batch(tea.Println("\n"+m.locale.F("Language: %s.", m.locale.Name)), m.visitor.savePrefs())
+
+	case key.Matches(msg, m.keys.Accessible):
+		m.accessible = false
+		m.syncKeys()
+		return m, tea.Batch(tea.EnterAltScreen, tea.EnableMouseAllMotion)
+
+	default:
+		return m, nil
+	}
+	return m, tea.Println(m.plainTab(m.activeTab))
+}
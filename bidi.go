@@ -0,0 +1,463 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+)
+
+// Terminals lay text out left to right, and most show right-to-left scripts
+// in the order they are sent, backwards. So a right-to-left locale's screen
+// is drawn as usual and then mirrored a line at a time, with a simplified
+// Unicode bidirectional algorithm: the rail, bullets and cards move to the
+// right, glyphs that point one way are swapped for their mirror images, and
+// Arabic and Hebrew words read right to left while runs of Latin text and
+// numbers keep their order.
+
+// Bidi isolates mark text that is laid out on its own and placed as a block.
+const (
+	lri = "⁦" // left to right
+	fsi = "⁨" // the direction of its first letter, left to right if none
+	pdi = "⁩" // end of isolate
+)
+
+// The bidi mode escapes. Terminals that reorder bidi text themselves are
+// told not to while the screen is mirrored, and are given back their
+// default when the session ends.
+const (
+	bidiExplicit = "\x1b[8l"
+	bidiImplicit = "\x1b[8h"
+)
+
+// ltr keeps text in order as a block when the screen is mirrored, for the
+// banner, whose lines are pictures rather than words.
+func (s styles) ltr(text string) string {
+	if !s.l.rtl() {
+		return text
+	}
+	return lri + text + pdi
+}
+
+// isolate keeps text together when the screen is mirrored, in the direction
+// of its first letter, for addresses and phone numbers that would otherwise
+// be split up by the text around them.
+func (s styles) isolate(text string) string {
+	if !s.l.rtl() {
+		return text
+	}
+	return fsi + text + pdi
+}
+
+// bidiKey is the session context key of an *atomic.Bool that is set once a
+// right-to-left locale has taken bidi reordering from the terminal.
+type bidiKey struct{}
+
+// resetBidiMode gives bidi reordering back to the terminal after a
+// session's program, if a right-to-left locale took it. Other sessions'
+// output is left as it is.
+func resetBidiMode(next ssh.Handler) ssh.Handler {
+	return func(s ssh.Session) {
+		next(s)
+		taken, ok := s.Context().Value(bidiKey{}).(*atomic.Bool)
+		if _, _, pty := s.Pty(); pty && ok && taken.Load() {
+			fmt.Fprint(s, bidiImplicit)
+		}
+	}
+}
+
+// mirrorPairs are glyphs and their mirror images.
+var mirrorPairs = [][2]string{
+	{"(", ")"}, {"[", "]"}, {"{", "}"}, {"<", ">"}, {"«", "»"}, {"‹", "›"},
+	{"▸", "◂"}, {"▶", "◀"}, {"►", "◄"}, {"→", "←"},
+	{"├", "┤"}, {"┌", "┐"}, {"└", "┘"}, {"╭", "╮"}, {"╰", "╯"},
+	{"╔", "╗"}, {"╚", "╝"}, {"┏", "┓"}, {"┗", "┛"}, {"╠", "╣"}, {"┣", "┫"},
+	{"▌", "▐"},
+}
+
+var mirrorGlyphs = func() map[string]string {
+	m := map[string]string{}
+	for _, p := range mirrorPairs {
+		m[p[0]], m[p[1]] = p[1], p[0]
+	}
+	return m
+}()
+
+// bidiClass is how a character takes part in reordering.
+type bidiClass int
+
+const (
+	bidiNeutral   bidiClass = iota // spaces, punctuation, box drawing
+	bidiLeft                       // Latin and other left-to-right letters
+	bidiRight                      // Arabic and Hebrew letters
+	bidiNumber                     // digits
+	bidiSeparator                  // punctuation that may join two numbers
+)
+
+func classOf(text string) bidiClass {
+	r, _ := utf8.DecodeRuneInString(text)
+	switch {
+	case unicode.IsDigit(r):
+		return bidiNumber
+	case strings.ContainsRune(".,:/+-", r):
+		return bidiSeparator
+	case unicode.In(r, unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana, unicode.Nko):
+		if unicode.IsLetter(r) || unicode.IsMark(r) {
+			return bidiRight
+		}
+	case unicode.IsLetter(r):
+		return bidiLeft
+	}
+	return bidiNeutral
+}
+
+// cell is one character on screen as drawn, with the styles and hyperlink
+// it was drawn with.
+type cell struct {
+	text  string // the character and any combining marks
+	style string // SGR sequences in effect
+	link  string // OSC 8 parameters and URL in effect
+}
+
+// parseCells splits a line of styled text into cells. Escape sequences
+// other than styles and hyperlinks are dropped.
+func parseCells(line string) []cell {
+	var cells []cell
+	var style, link string
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' && i+1 < len(line) {
+			switch line[i+1] {
+			case '[':
+				j := i + 2
+				for j < len(line) && (line[j] < 0x40 || line[j] > 0x7e) {
+					j++
+				}
+				if j == len(line) {
+					return cells
+				}
+				if line[j] == 'm' {
+					switch params := line[i+2 : j]; {
+					case params == "" || params == "0":
+						style = ""
+					case strings.HasPrefix(params, "0;"):
+						style = line[i : j+1]
+					default:
+						style += line[i : j+1]
+					}
+				}
+				i = j + 1
+			case ']':
+				j, end := i+2, len(line)
+				for ; j < len(line); j++ {
+					if line[j] == '\a' {
+						end = j + 1
+						break
+					}
+					if line[j] == '\x1b' && j+1 < len(line) && line[j+1] == '\\' {
+						end = j + 2
+						break
+					}
+				}
+				if osc := line[i+2 : j]; strings.HasPrefix(osc, "8;") {
+					link = osc[2:]
+					if strings.HasSuffix(link, ";") {
+						link = ""
+					}
+				}
+				i = end
+			default:
+				i += 2
+			}
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(line[i:])
+		text := line[i : i+size]
+		i += size
+		isolate := text == lri || text == fsi || text == pdi
+		if !isolate && lipgloss.Width(text) == 0 && len(cells) > 0 && r != '\t' {
+			cells[len(cells)-1].text += text
+			continue
+		}
+		cells = append(cells, cell{text: text, style: style, link: link})
+	}
+	return cells
+}
+
+// renderCells draws cells back out as styled text.
+func renderCells(cells []cell) string {
+	var b strings.Builder
+	var style, link string
+	for _, c := range cells {
+		if c.link != link {
+			b.WriteString("\x1b]8;" + c.link)
+			if c.link == "" {
+				b.WriteString(";")
+			}
+			b.WriteString("\x1b\\")
+			link = c.link
+		}
+		if c.style != style {
+			b.WriteString("\x1b[0m" + c.style)
+			style = c.style
+		}
+		b.WriteString(c.text)
+	}
+	if link != "" {
+		b.WriteString("\x1b]8;;\x1b\\")
+	}
+	if style != "" {
+		b.WriteString("\x1b[0m")
+	}
+	return b.String()
+}
+
+// bidiUnit is a cell, or an isolate with its cells already in display
+// order, which counts as neutral in the text around it.
+type bidiUnit struct {
+	cells   []cell
+	class   bidiClass
+	isolate bool
+}
+
+// reorder puts cells in logical order into display order, in a line that
+// reads right to left if rtl is set.
+func reorder(cells []cell, rtl bool) []cell {
+	var units []bidiUnit
+	for i := 0; i < len(cells); i++ {
+		switch cells[i].text {
+		case lri, fsi:
+			j, depth := i+1, 1
+			for ; j < len(cells); j++ {
+				if t := cells[j].text; t == lri || t == fsi {
+					depth++
+				} else if t == pdi {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+			inner := cells[i+1 : min(j, len(cells))]
+			innerRTL := cells[i].text == fsi && firstStrong(inner) == bidiRight
+			units = append(units, bidiUnit{cells: reorder(inner, innerRTL), isolate: true})
+			i = j
+		case pdi:
+		default:
+			units = append(units, bidiUnit{cells: cells[i : i+1], class: classOf(cells[i].text)})
+		}
+	}
+
+	levels := resolveLevels(units, rtl)
+	for i, u := range units {
+		if levels[i]%2 == 1 && !u.isolate {
+			if m, ok := mirrorGlyphs[u.cells[0].text]; ok {
+				c := u.cells[0]
+				c.text = m
+				units[i].cells = []cell{c}
+			}
+		}
+	}
+
+	// Reverse each run at or above every odd level, highest first.
+	highest := 0
+	for _, l := range levels {
+		highest = max(highest, l)
+	}
+	for level := highest; level >= 1; level-- {
+		for i := 0; i < len(units); {
+			if levels[i] < level {
+				i++
+				continue
+			}
+			j := i
+			for j < len(units) && levels[j] >= level {
+				j++
+			}
+			for a, b := i, j-1; a < b; a, b = a+1, b-1 {
+				units[a], units[b] = units[b], units[a]
+				levels[a], levels[b] = levels[b], levels[a]
+			}
+			i = j
+		}
+	}
+
+	out := make([]cell, 0, len(cells))
+	for _, u := range units {
+		out = append(out, u.cells...)
+	}
+	return out
+}
+
+func firstStrong(cells []cell) bidiClass {
+	for _, c := range cells {
+		if class := classOf(c.text); class == bidiLeft || class == bidiRight {
+			return class
+		}
+	}
+	return bidiLeft
+}
+
+// resolveLevels gives each unit its embedding level: 0 for left to right and
+// 1 for right to left, with text that runs against the line one higher.
+func resolveLevels(units []bidiUnit, rtl bool) []int {
+	base := bidiLeft
+	if rtl {
+		base = bidiRight
+	}
+	classes := make([]bidiClass, len(units))
+	for i, u := range units {
+		classes[i] = u.class
+	}
+
+	// A lone separator between two numbers joins them: 2019-2021, 3.5.
+	for i := range classes {
+		if classes[i] != bidiSeparator {
+			continue
+		}
+		if i > 0 && i < len(classes)-1 && classes[i-1] == bidiNumber && classes[i+1] == bidiNumber {
+			classes[i] = bidiNumber
+		} else {
+			classes[i] = bidiNeutral
+		}
+	}
+
+	// Numbers in left-to-right text are part of it.
+	prev := base
+	for i, c := range classes {
+		switch c {
+		case bidiLeft, bidiRight:
+			prev = c
+		case bidiNumber:
+			if prev == bidiLeft {
+				classes[i] = bidiLeft
+			}
+		}
+	}
+
+	// Neutrals take the direction of the text on both sides if it agrees,
+	// and the line's otherwise. Numbers count as right to left here.
+	strong := func(c bidiClass) bidiClass {
+		if c == bidiNumber {
+			return bidiRight
+		}
+		return c
+	}
+
+	// Brackets take the direction of what they enclose, so that the pair in
+	// "Dell-EMC (subsidiary)" stays with its text.
+	var open []int
+	for i, u := range units {
+		if u.isolate || classes[i] != bidiNeutral {
+			continue
+		}
+		switch t := u.cells[0].text; t {
+		case "(", "[", "{":
+			open = append(open, i)
+		case ")", "]", "}":
+			k := len(open) - 1
+			for k >= 0 && mirrorGlyphs[units[open[k]].cells[0].text] != t {
+				k--
+			}
+			if k < 0 {
+				continue
+			}
+			o := open[k]
+			open = open[:k]
+			inside := map[bidiClass]bool{}
+			for _, c := range classes[o+1 : i] {
+				inside[strong(c)] = true
+			}
+			other := bidiLeft
+			if base == bidiLeft {
+				other = bidiRight
+			}
+			dir := bidiNeutral
+			switch {
+			case inside[base]:
+				dir = base
+			case inside[other]:
+				dir = base
+				for p := o - 1; p >= 0; p-- {
+					if c := strong(classes[p]); c == bidiLeft || c == bidiRight {
+						if c == other {
+							dir = other
+						}
+						break
+					}
+				}
+			}
+			if dir != bidiNeutral {
+				classes[o], classes[i] = dir, dir
+			}
+		}
+	}
+	for i := 0; i < len(classes); {
+		if classes[i] != bidiNeutral {
+			i++
+			continue
+		}
+		j := i
+		for j < len(classes) && classes[j] == bidiNeutral {
+			j++
+		}
+		before, after := base, base
+		if i > 0 {
+			before = strong(classes[i-1])
+		}
+		if j < len(classes) {
+			after = strong(classes[j])
+		}
+		dir := base
+		if before == after {
+			dir = before
+		}
+		for k := i; k < j; k++ {
+			classes[k] = dir
+		}
+		i = j
+	}
+
+	levels := make([]int, len(classes))
+	for i, c := range classes {
+		switch {
+		case c == bidiNumber:
+			levels[i] = 2
+		case c == bidiRight:
+			levels[i] = 1
+		case rtl:
+			levels[i] = 2
+		}
+	}
+	return levels
+}
+
+// mirror lays out a screen width cells wide, drawn left to right, for a
+// right-to-left locale.
+func mirror(view string, width int) string {
+	lines := strings.Split(view, "\n")
+	for i, line := range lines {
+		// Lines are cut or padded to the width first, so that what the
+		// terminal would have cut off the right isn't moved to the left.
+		lines[i] = renderCells(reorder(fitCells(parseCells(line), width), true))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fitCells cuts cells to width cells on screen, or pads them with spaces.
+func fitCells(cells []cell, width int) []cell {
+	w := 0
+	for j, c := range cells {
+		if w += lipgloss.Width(c.text); w > width {
+			cells, w = cells[:j], w-lipgloss.Width(c.text)
+			break
+		}
+	}
+	for ; w < width; w++ {
+		cells = append(cells, cell{text: " "})
+	}
+	return cells
+}
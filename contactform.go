@@ -0,0 +1,365 @@
+package main
+
+import (
+	"log"
+	"math"
+	"net/mail"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The contact form lets visitors write a message from the Contact tab: their
+// name, an address to reply to and the message, delivered to the app's
+// inbox. Each IP address can send contactLimit messages every contactWindow.
+
+const (
+	contactMaxName = 64   // characters in a name
+	contactMaxAddr = 254  // characters in an email address
+	contactMaxLen  = 2000 // characters in a message
+	contactLimit   = 3
+	contactWindow  = time.Hour
+)
+
+// The contact form's fields, in tab order.
+const (
+	fieldName = iota
+	fieldReplyTo
+	fieldMessage
+	contactFields
+)
+
+var contactLabels = [contactFields]string{"Name", "Reply-to", "Message"}
+
+// contactForm is the state of the contact form. sending is when the message
+// on its way was sent, zero when none is, and sent is set once it is
+// delivered, which turns the form into its confirmation.
+type contactForm struct {
+	name    textinput.Model
+	replyTo textinput.Model
+	message textarea.Model
+	focus   int
+	problem string // why the message was turned down or not sent
+	sending time.Time
+	sent    bool
+}
+
+// contactSentMsg reports how sending the message sent at at went: wait is
+// how long until the visitor's IP address may send again if it sent too
+// many.
+type contactSentMsg struct {
+	at   time.Time
+	wait time.Duration
+	err  error
+}
+
+// throttle limits how often each IP address may send the contact form or
+// sign the guestbook. It is kept in memory, so a restart forgets it.
+type throttle struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	sent   map[string][]time.Time
+}
+
+func newThrottle(limit int, window time.Duration) *throttle {
+	return &throttle{limit: limit, window: window, sent: map[string][]time.Time{}}
+}
+
+// take counts a message from ip at now, or, if ip has already sent limit
+// messages in the window, returns how long until it may send again.
+func (t *throttle) take(ip string, now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for k, times := range t.sent {
+		var recent []time.Time
+		for _, at := range times {
+			if now.Sub(at) < t.window {
+				recent = append(recent, at)
+			}
+		}
+		if len(recent) == 0 {
+			delete(t.sent, k)
+		} else {
+			t.sent[k] = recent
+		}
+	}
+	if times := t.sent[ip]; len(times) >= t.limit {
+		return times[0].Add(t.window).Sub(now)
+	}
+	t.sent[ip] = append(t.sent[ip], now)
+	return 0
+}
+
+// refund uncounts the message take counted from ip at at.
+func (t *throttle) refund(ip string, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	times := t.sent[ip]
+	for i := len(times) - 1; i >= 0; i-- {
+		if times[i].Equal(at) {
+			t.sent[ip] = append(times[:i:i], times[i+1:]...)
+			return
+		}
+	}
+}
+
+// openContactForm opens an empty contact form.
+func (m model) openContactForm() model {
+	s := m.styles
+	w := min(m.width-16, 60)
+	// Leave room for the rest of the form: the other fields, the labels,
+	// the blank lines between them and the box take 17 lines.
+	h := max(min(m.viewport.Height-17, 6), 2)
+	if m.accessible {
+		h = 1
+	}
+	m.contact = contactForm{
+		name:    s.textInput(m.locale.T("Your name"), contactMaxName, w),
+		replyTo: s.textInput("you@example.com", contactMaxAddr, w),
+		message: s.textArea(m.locale.T("Say hello…"), contactMaxLen, w, h),
+	}
+	m.contact.setFocus(fieldName)
+	return m.setOverlay(contactOverlay)
+}
+
+// setFocus moves the keyboard to field i.
+func (f *contactForm) setFocus(i int) {
+	f.focus = i
+	f.name.Blur()
+	f.replyTo.Blur()
+	f.message.Blur()
+	switch i {
+	case fieldName:
+		f.name.Focus()
+	case fieldReplyTo:
+		f.replyTo.Focus()
+	case fieldMessage:
+		f.message.Focus()
+	}
+}
+
+// updateContactForm handles keys while the contact form is open: the field
+// keys move between fields, select moves on from a one-line field, send
+// sends and close cancels, or stops waiting while the message is on its
+// way. Once sent, select or close dismisses the confirmation.
+func (m model) updateContactForm(msg tea.KeyMsg) (model, tea.Cmd) {
+	f := &m.contact
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return m, tea.Quit
+	case f.sent:
+		if key.Matches(msg, m.keys.Close, m.keys.Select) {
+			return m.setOverlay(noOverlay), nil
+		}
+		return m, nil
+	case !f.sending.IsZero():
+		if !key.Matches(msg, m.keys.Close) {
+			return m, nil
+		}
+		// The message can't be called back, so the form stops waiting for
+		// it and contactSent ignores how it went.
+		f.sending = time.Time{}
+		m = m.setOverlay(noOverlay)
+		if m.accessible {
+			return m, tea.Println(m.locale.T("Stopped waiting. The message may still arrive."))
+		}
+		return m.setNotice(m.locale.T("Stopped waiting. The message may still arrive."))
+	case key.Matches(msg, m.keys.Close):
+		m = m.setOverlay(noOverlay)
+		if m.accessible {
+			return m, tea.Println(m.locale.T("Message discarded."))
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.NextField), key.Matches(msg, m.keys.Select) && f.focus != fieldMessage:
+		f.setFocus((f.focus + 1) % contactFields)
+		return m, m.announceField()
+	case key.Matches(msg, m.keys.PrevField):
+		f.setFocus((f.focus - 1 + contactFields) % contactFields)
+		return m, m.announceField()
+	case key.Matches(msg, m.keys.Send):
+		cm := contactMessage{
+			Name:        cleanText(f.name.Value()),
+			ReplyTo:     strings.TrimSpace(f.replyTo.Value()),
+			Message:     cleanMessage(f.message.Value()),
+			Time:        time.Now().UTC(),
+			User:        cleanText(m.visitor.user),
+			Fingerprint: m.visitor.fingerprint,
+			IP:          m.visitor.ip,
+		}
+		if p, field := contactProblem(cm); p != "" {
+			f.problem = m.locale.T(p)
+			f.setFocus(field)
+			if m.accessible {
+				return m, tea.Println(f.problem)
+			}
+			return m, nil
+		}
+		f.problem = ""
+		f.sending = cm.Time
+		in, limit := m.app.inbox, m.app.throttle
+		return m, func() tea.Msg {
+			if wait := limit.take(cm.IP, time.Now()); wait > 0 {
+				return contactSentMsg{at: cm.Time, wait: wait}
+			}
+			return contactSentMsg{at: cm.Time, err: in.deliver(cm)}
+		}
+	}
+	var cmd tea.Cmd
+	switch f.focus {
+	case fieldName:
+		f.name, cmd = f.name.Update(msg)
+	case fieldReplyTo:
+		f.replyTo, cmd = f.replyTo.Update(msg)
+	case fieldMessage:
+		f.message, cmd = f.message.Update(msg)
+	}
+	f.problem = ""
+	return m, cmd
+}
+
+// announceField prints the focused field's name in accessible mode, where
+// the status line alone would go unnoticed.
+func (m model) announceField() tea.Cmd {
+	if !m.accessible {
+		return nil
+	}
+	return tea.Println(m.locale.T(contactLabels[m.contact.focus]))
+}
+
+// contactSent shows the confirmation once the message is delivered, or says
+// why it wasn't. Failures are logged even if the visitor stopped waiting.
+func (m model) contactSent(msg contactSentMsg) (model, tea.Cmd) {
+	f := &m.contact
+	if msg.err != nil {
+		log.Printf("Could not deliver contact message: %v", msg.err)
+	}
+	if m.overlay != contactOverlay || !msg.at.Equal(f.sending) {
+		return m, nil
+	}
+	f.sending = time.Time{}
+	switch {
+	case msg.err != nil:
+		f.problem = m.locale.T("Something went wrong. Please try again later.")
+	case msg.wait > 0:
+		f.problem = m.locale.F("You've sent a few messages already. Please try again in %d min.", int(math.Ceil(msg.wait.Minutes())))
+	default:
+		f.sent = true
+		if m.accessible {
+			m = m.setOverlay(noOverlay)
+			return m, tea.Println(m.sentText())
+		}
+		return m, nil
+	}
+	if m.accessible {
+		return m, tea.Println(f.problem)
+	}
+	return m, nil
+}
+
+// sentText confirms a message is on its way.
+func (m model) sentText() string {
+	name, addr := cleanText(m.contact.name.Value()), strings.TrimSpace(m.contact.replyTo.Value())
+	return m.locale.F("Thanks, %s! Your message is on its way, and I'll reply to %s.", m.styles.isolate(name), m.styles.isolate(addr))
+}
+
+// cleanMessage is like cleanText, but keeps line breaks, with at most one
+// blank line between paragraphs.
+func cleanMessage(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var out []string
+	for _, line := range lines {
+		line = cleanText(line)
+		if line == "" && (len(out) == 0 || out[len(out)-1] == "") {
+			continue
+		}
+		out = append(out, line)
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// contactProblem is why msg can't be sent and the field to fix, or "" if
+// it can.
+func contactProblem(msg contactMessage) (string, int) {
+	switch {
+	case msg.Name == "":
+		return "Please tell me your name.", fieldName
+	case len([]rune(msg.Name)) > contactMaxName:
+		return "That name is too long.", fieldName
+	case msg.ReplyTo == "":
+		return "Please give an email address to reply to.", fieldReplyTo
+	case !validAddress(msg.ReplyTo):
+		return "That doesn't look like an email address.", fieldReplyTo
+	case msg.Message == "":
+		return "Write a message first.", fieldMessage
+	case len([]rune(msg.Message)) > contactMaxLen:
+		return "That message is too long.", fieldMessage
+	}
+	return "", 0
+}
+
+// validAddress reports whether addr is a bare email address with a domain.
+func validAddress(addr string) bool {
+	a, err := mail.ParseAddress(addr)
+	if err != nil || a.Address != addr || len(addr) > contactMaxAddr {
+		return false
+	}
+	_, domain, _ := strings.Cut(addr, "@")
+	return strings.Contains(domain, ".") && !strings.ContainsFunc(addr, unicode.IsSpace)
+}
+
+// contactHint says how to open the contact form, or "" without an inbox.
+func (m model) contactHint() string {
+	if m.app.inbox == nil {
+		return ""
+	}
+	return m.locale.F("Press %s to send me a message.", m.keys.Compose.Help().Key)
+}
+
+// renderContactForm is the box the contact form is drawn in, without the
+// blank lines between fields if it would not fit otherwise.
+func (s styles) renderContactForm(f contactForm, hint string, width, height int) string {
+	views := [contactFields]string{f.name.View(), f.replyTo.View(), f.message.View()}
+	form := func(gap []string) string {
+		rows := []string{s.accentText.Render(s.l.T("Send me a message"))}
+		for i, v := range views {
+			label := s.dimText
+			if i == f.focus {
+				label = s.secondaryText
+			}
+			rows = append(append(rows, gap...), label.Render(s.l.T(contactLabels[i])), v)
+		}
+		switch {
+		case !f.sending.IsZero():
+			rows = append(append(rows, gap...), s.dimText.Render(s.l.T("Sending…")))
+		case f.problem != "":
+			rows = append(append(rows, gap...), s.r.NewStyle().Foreground(s.theme.Pink).Render(f.problem))
+		}
+		rows = append(append(rows, gap...), hint)
+		return lipgloss.JoinVertical(lipgloss.Left, rows...)
+	}
+	content := form([]string{""})
+	if lipgloss.Height(content)+boxFrame > height {
+		content = form(nil)
+	}
+	return s.placeBox(content, width, height)
+}
+
+// renderContactSent is the confirmation shown once a message is sent.
+func (s styles) renderContactSent(text, hint string, width, height int) string {
+	rows := []string{
+		s.accentText.Render(s.l.T("Message sent")),
+		"",
+		s.r.NewStyle().Width(min(width-16, 60)).Foreground(s.theme.Text).Render(text),
+		"",
+		hint,
+	}
+	return s.placeBox(lipgloss.JoinVertical(lipgloss.Left, rows...), width, height)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"ada@example.com", true},
+		{"ada.lovelace+portfolio@mail.example.co.uk", true},
+		{"ada@localhost", false},
+		{"ada", false},
+		{"Ada <ada@example.com>", false},
+		{" ada@example.com", false},
+		{"ada@exa mple.com", false},
+		{strings.Repeat("a", contactMaxAddr) + "@example.com", false},
+	}
+	for _, tt := range tests {
+		if got := validAddress(tt.addr); got != tt.want {
+			t.Errorf("validAddress(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestContactProblem(t *testing.T) {
+	ok := contactMessage{Name: "Ada", ReplyTo: "ada@example.com", Message: "Hello"}
+	tests := []struct {
+		name  string
+		edit  func(*contactMessage)
+		field int
+		none  bool
+	}{
+		{name: "complete", edit: func(*contactMessage) {}, none: true},
+		{name: "no name", edit: func(m *contactMessage) { m.Name = "" }, field: fieldName},
+		{name: "long name", edit: func(m *contactMessage) { m.Name = strings.Repeat("é", contactMaxName+1) }, field: fieldName},
+		{name: "no address", edit: func(m *contactMessage) { m.ReplyTo = "" }, field: fieldReplyTo},
+		{name: "bad address", edit: func(m *contactMessage) { m.ReplyTo = "ada" }, field: fieldReplyTo},
+		{name: "no message", edit: func(m *contactMessage) { m.Message = "" }, field: fieldMessage},
+		{name: "longest message", edit: func(m *contactMessage) { m.Message = strings.Repeat("é", contactMaxLen) }, none: true},
+		{name: "long message", edit: func(m *contactMessage) { m.Message = strings.Repeat("é", contactMaxLen+1) }, field: fieldMessage},
+	}
+	for _, tt := range tests {
+		msg := ok
+		tt.edit(&msg)
+		problem, field := contactProblem(msg)
+		if tt.none {
+			if problem != "" {
+				t.Errorf("%s: got problem %q", tt.name, problem)
+			}
+			continue
+		}
+		if problem == "" || field != tt.field {
+			t.Errorf("%s: got %q for field %d, want a problem with field %d", tt.name, problem, field, tt.field)
+		}
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	start := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	th := newThrottle(2, time.Hour)
+
+	for i := range 2 {
+		if wait := th.take("192.0.2.1", start.Add(time.Duration(i)*time.Minute)); wait != 0 {
+			t.Fatalf("message %d: wait %v, want none", i+1, wait)
+		}
+	}
+	if wait := th.take("192.0.2.1", start.Add(10*time.Minute)); wait != 50*time.Minute {
+		t.Errorf("third message: wait %v, want 50m", wait)
+	}
+	if wait := th.take("192.0.2.2", start.Add(10*time.Minute)); wait != 0 {
+		t.Errorf("another address: wait %v, want none", wait)
+	}
+	if wait := th.take("192.0.2.1", start.Add(time.Hour)); wait != 0 {
+		t.Errorf("after the window: wait %v, want none", wait)
+	}
+	if wait := th.take("192.0.2.1", start.Add(time.Hour+30*time.Second)); wait == 0 {
+		t.Error("limit not reapplied once the window moved on")
+	}
+}
@@ -1,5 +1,7 @@
 package main
 
+import "time"
+
 // Profile holds the top-level bio information.
 type Profile struct {
 	Name     string
@@ -8,10 +10,13 @@ type Profile struct {
 	Bio      string
 }
 
-// Experience represents a single work history entry.
+// Experience represents a single work history entry. End is Present for a
+// current role; Period, when set, replaces the generated date range text.
 type Experience struct {
 	Title       string
 	Company     string
+	Start       Date
+	End         Date
 	Period      string
 	Description string
 	Highlights  []string
@@ -22,27 +27,45 @@ type Project struct {
 	Name        string
 	Description string
 	Tech        []string
-	URL         string
+	URL         string // https is assumed when it has no scheme
 }
 
 // SkillGroup is a named category of skills.
 type SkillGroup struct {
-	Category string
-	Skills   []string
+	Category string   `json:"category,omitempty"`
+	Skills   []string `json:"skills,omitempty"`
 }
 
-// Education represents a degree or certification.
-type Education struct {
-	Degree      string
-	Institution string
-	Period      string
-	Details     string
+// Entry is one item of a timeline or cards tab: a degree, a talk, a
+// publication. Start may be left unset to show only the End date; Period,
+// when set, replaces the generated date text. Cards show the Tags and URL.
+type Entry struct {
+	Title       string   `json:"title,omitempty"`
+	Subtitle    string   `json:"subtitle,omitempty"`
+	Start       Date     `json:"start,omitzero"`
+	End         Date     `json:"end,omitzero"`
+	Period      string   `json:"period,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	URL         string   `json:"url,omitempty"`
 }
 
 // ContactInfo holds a single contact method.
 type ContactInfo struct {
-	Label string
-	Value string
+	Label string `json:"label,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// portfolio is what the built-in tabs show: the profile, experiences,
+// projects, skill groups and contacts. builtinPortfolio is the one below; a
+// content file can replace each part of it (see portfolioOf).
+type portfolio struct {
+	profile     Profile
+	experiences []Experience
+	projects    []Project
+	skillGroups []SkillGroup
+	contacts    []ContactInfo
 }
 
 // --- Portfolio data ---
@@ -63,7 +86,8 @@ var experiences = []Experience{
 	{
 		Title:       "Senior Software Engineer",
 		Company:     "Inari Agriculture",
-		Period:      "Jun 2021 — Present",
+		Start:       Date{2021, time.June},
+		End:         Present,
 		Description: "Backend infrastructure, API development, and cloud platform engineering.",
 		Highlights: []string{
 			"Develops RESTful APIs utilizing Flask, SQLAlchemy, PostgreSQL, Celery, and Redis for streamlined computational jobs and data access",
@@ -78,7 +102,8 @@ var experiences = []Experience{
 	{
 		Title:       "Senior Analyst & Developer",
 		Company:     "RSA AFCC (Dell-EMC subsidiary)",
-		Period:      "Jan 2019 — May 2021",
+		Start:       Date{2019, time.January},
+		End:         Date{2021, time.May},
 		Description: "Internal tooling and international anti-fraud operations.",
 		Highlights: []string{
 			"Developed web-based internal tools for quality assurance with full SQL database connectivity and email reports",
@@ -88,7 +113,8 @@ var experiences = []Experience{
 	{
 		Title:       "Senior Capstone Project — Minecraft",
 		Company:     "Mojang Studios",
-		Period:      "Aug 2020 — Dec 2020",
+		Start:       Date{2020, time.August},
+		End:         Date{2020, time.December},
 		Description: "Collaborated with Mojang on Minecraft's save system.",
 		Highlights: []string{
 			"Researched plausible updates to Minecraft's save system for more efficient data access and resilience against data loss",
@@ -159,12 +185,12 @@ var skillGroups = []SkillGroup{
 	},
 }
 
-var education = []Education{
+var education = []Entry{
 	{
-		Degree:      "B.S. Computer Science",
-		Institution: "Purdue University, West Lafayette, IN",
-		Period:      "May 2021",
-		Details:     "Focus in Software Engineering and Security.",
+		Title:       "B.S. Computer Science",
+		Subtitle:    "Purdue University, West Lafayette, IN",
+		End:         Date{2021, time.May},
+		Description: "Focus in Software Engineering and Security.",
 	},
 }
 
@@ -177,3 +203,35 @@ var contacts = []ContactInfo{
 	{Label: "LinkedIn", Value: "linkedin.com/in/vaughancodes"},
 	{Label: "Location", Value: "West Lafayette, IN"},
 }
+
+var builtinPortfolio = portfolio{
+	profile:     profile,
+	experiences: experiences,
+	projects:    projects,
+	skillGroups: skillGroups,
+	contacts:    contacts,
+}
+
+// builtinTabs are the tabs in the order they appear. Those without content of
+// their own show the portfolio above: the profile, experiences, projects,
+// skill groups and contacts.
+var builtinTabs = []Tab{
+	{Title: "About", Order: 1, Type: sectionBio},
+	{Title: "Experience", Order: 2, Type: sectionTimeline},
+	{Title: "Projects", Order: 3, Type: sectionCards},
+	{Title: "Education", Order: 4, Type: sectionTimeline, Heading: "Education & Certifications", Entries: education},
+	{Title: "Skills", Order: 5, Type: sectionTags},
+	{Title: "Contact", Order: 6, Type: sectionList},
+}
+
+// skillAliases lists extra terms that count as evidence for a skill on top of
+// the skill's own name. Use it where the wording in experience highlights or
+// project tech differs from the label shown on the Skills tab.
+var skillAliases = map[string][]string{
+	"C/C++":                        {"C++"},
+	"SQL":                          {"PostgreSQL"},
+	"AWS (EC2, EKS, RDS, ECR, S3)": {"AWS", "EC2", "EKS", "RDS", "ECR", "S3"},
+	"Kubernetes":                   {"EKS"},
+	"Stakeholder Collaboration":    {"client needs", "external consultants"},
+	"Cross-team Coordination":      {"international team"},
+}
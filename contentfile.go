@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// contentRefresh is how often the content file is checked for changes.
+const contentRefresh = 2 * time.Second
+
+// errContentChanged is returned by save when the content file changed since
+// the caller read it.
+var errContentChanged = errors.New("the content file changed since it was opened")
+
+// contentFile is the -tabs content file and the content files of its
+// locales, shared by every session. The files are loaded again whenever they
+// change on disk, and version counts the times they have been, so sessions
+// can tell when to rebuild their tab bars. Until the main file exists, the
+// built-in tabs stand in for it.
+type contentFile struct {
+	path    string // "" for the built-in tabs alone
+	locales []*locale
+	saving  sync.Mutex // held while a save is under way
+
+	mu         sync.RWMutex
+	stamp      string // the files' sizes and modification times when loaded
+	tabs       []Tab
+	localeTabs map[string][]Tab
+	version    int
+}
+
+// openContent loads the content file at path and those of its locales, or
+// uses the built-in tabs for "".
+func openContent(path string, locales []*locale) (*contentFile, error) {
+	c := &contentFile{path: path, locales: locales, tabs: builtinTabs}
+	if path == "" {
+		return c, nil
+	}
+	if _, err := c.reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// tabsIn is the tabs of locale tag's content file, if it has one, and of the
+// main one otherwise.
+func (c *contentFile) tabsIn(tag string) []Tab {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if tabs, ok := c.localeTabs[tag]; ok {
+		return tabs
+	}
+	return c.tabs
+}
+
+// current is the number of times the files have been loaded.
+func (c *contentFile) current() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.version
+}
+
+// fileStamp sums up the sizes and modification times of the content files,
+// to tell when one has changed.
+func (c *contentFile) fileStamp() string {
+	ext := filepath.Ext(c.path)
+	paths := []string{c.path}
+	for _, l := range c.locales {
+		paths = append(paths, strings.TrimSuffix(c.path, ext)+"."+l.Tag+ext)
+	}
+	var b strings.Builder
+	for _, p := range paths {
+		if info, err := os.Stat(p); err == nil {
+			fmt.Fprintf(&b, "%s %d %d\n", p, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return b.String()
+}
+
+// reload loads the files again if they changed since they were last loaded,
+// and reports whether they did. If they don't load, the tabs stay as they
+// were, and the error is only returned once per change.
+func (c *contentFile) reload() (bool, error) {
+	if c.path == "" {
+		return false, nil
+	}
+	stamp := c.fileStamp()
+	c.mu.RLock()
+	same := stamp == c.stamp
+	c.mu.RUnlock()
+	if same {
+		return false, nil
+	}
+
+	tabs, err := loadTabs(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		tabs, err = builtinTabs, nil
+	}
+	var localeTabs map[string][]Tab
+	if err == nil {
+		localeTabs, err = loadLocaleTabs(c.path, c.locales)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stamp = stamp
+	if err != nil {
+		return false, err
+	}
+	c.tabs, c.localeTabs = tabs, localeTabs
+	c.version++
+	return true, nil
+}
+
+// watch reloads the files every contentRefresh.
+func (c *contentFile) watch() {
+	if c.path == "" {
+		return
+	}
+	for {
+		time.Sleep(contentRefresh)
+		changed, err := c.reload()
+		switch {
+		case err != nil:
+			log.Printf("Could not reload tabs: %v", err)
+		case changed:
+			log.Printf("Reloaded %s", c.path)
+		}
+	}
+}
+
+// read returns the main content file as it is on disk, or nothing if there
+// isn't one yet.
+func (c *contentFile) read() ([]byte, error) {
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// save replaces the main content file with data, which must hold valid tabs,
+// keeping the old file beside it with a .bak suffix, and reloads it. The
+// first save creates the file, and its directory if need be. base is the file
+// as the caller read it; if it has changed since, save returns
+// errContentChanged rather than overwrite someone else's changes.
+func (c *contentFile) save(base, data []byte) error {
+	c.saving.Lock()
+	defer c.saving.Unlock()
+	old, err := c.read()
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(old, base) {
+		return errContentChanged
+	}
+	if _, err := parseTabs(c.path, data); err != nil {
+		return err
+	}
+	perm := fs.FileMode(0o644)
+	if info, err := os.Stat(c.path); err == nil {
+		perm = info.Mode().Perm()
+		if err := os.WriteFile(c.path+".bak", old, perm); err != nil {
+			return err
+		}
+	} else if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+
+	// Write beside the file and rename over it, so that nothing ever reads
+	// half of it.
+	f, err := os.CreateTemp(filepath.Dir(c.path), "."+filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), c.path); err != nil {
+		return err
+	}
+	_, err = c.reload()
+	return err
+}
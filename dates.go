@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Date is a month-precision calendar date, the precision portfolio periods
+// are kept at.
+type Date struct {
+	Year  int
+	Month time.Month
+}
+
+// Present is the End of an entry that is still ongoing. It sorts after every
+// real date.
+var Present = Date{Year: 9999, Month: time.December}
+
+// IsZero reports whether d is unset.
+func (d Date) IsZero() bool {
+	return d == Date{}
+}
+
+// IsPresent reports whether d is the Present sentinel.
+func (d Date) IsPresent() bool {
+	return d == Present
+}
+
+// Time returns the first instant of d's month, or of the current month for
+// Present.
+func (d Date) Time() time.Time {
+	if d.IsPresent() {
+		now := time.Now()
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(d.Year, d.Month, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Before reports whether d is earlier than o.
+func (d Date) Before(o Date) bool {
+	return d.Year < o.Year || d.Year == o.Year && d.Month < o.Month
+}
+
+func (d Date) String() string {
+	if d.IsPresent() {
+		return "Present"
+	}
+	return d.Time().Format("Jan 2006")
+}
+
+// UnmarshalJSON reads a date written as "2021-06", or "present" for Present.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if strings.EqualFold(s, "present") {
+		*d = Present
+		return nil
+	}
+	t, err := time.Parse("2006-01", s)
+	if err != nil {
+		return fmt.Errorf("date %q: want YYYY-MM or present", s)
+	}
+	*d = Date{t.Year(), t.Month()}
+	return nil
+}
+
+// MarshalJSON writes d as UnmarshalJSON reads it.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsPresent() {
+		return json.Marshal("present")
+	}
+	return json.Marshal(d.Time().Format("2006-01"))
+}
+
+// formatPeriod renders a date range such as "Jan 2019 — May 2021". A missing
+// or identical start collapses it to a single date.
+func formatPeriod(start, end Date) string {
+	if start.IsZero() || start == end {
+		return end.String()
+	}
+	return start.String() + " — " + end.String()
+}
+
+// monthsBetween counts whole months from a to b.
+func monthsBetween(a, b time.Time) int {
+	return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+}
+
+// tenure renders the inclusive length of a period as e.g. "3 yrs 4 mos". It
+// returns "" for single-date periods.
+func tenure(start, end Date) string {
+	if start.IsZero() || end.IsZero() {
+		return ""
+	}
+	months := monthsBetween(start.Time(), end.Time()) + 1
+	if months <= 0 {
+		return ""
+	}
+
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("%d %s", n, unit)
+		}
+		return fmt.Sprintf("%d %ss", n, unit)
+	}
+	var parts []string
+	if y := months / 12; y > 0 {
+		parts = append(parts, plural(y, "yr"))
+	}
+	if mo := months % 12; mo > 0 {
+		parts = append(parts, plural(mo, "mo"))
+	}
+	return strings.Join(parts, " ")
+}
+
+// PeriodText is the period shown for an experience: the Period override when
+// set, otherwise the formatted Start and End.
+func (e Experience) PeriodText() string {
+	if e.Period != "" {
+		return e.Period
+	}
+	return formatPeriod(e.Start, e.End)
+}
+
+// Tenure is how long the role lasted, e.g. "3 yrs 4 mos".
+func (e Experience) Tenure() string {
+	return tenure(e.Start, e.End)
+}
+
+// PeriodText is the period shown for an entry: the Period override when set,
+// otherwise the formatted Start and End, or "" for an undated entry.
+func (e Entry) PeriodText() string {
+	switch {
+	case e.Period != "":
+		return e.Period
+	case e.End.IsZero() && e.Start.IsZero():
+		return ""
+	case e.End.IsZero():
+		return e.Start.String()
+	}
+	return formatPeriod(e.Start, e.End)
+}
+
+// sortedExperiences returns p's experiences most recent first: ongoing roles
+// lead, then by end date and start date, newest first.
+func (p portfolio) sortedExperiences() []Experience {
+	out := append([]Experience(nil), p.experiences...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return newerPeriod(out[i].Start, out[i].End, out[j].Start, out[j].End)
+	})
+	return out
+}
+
+// sortedEntries returns entries most recent first, an entry with only a
+// Start counting as ending then. Undated entries go last, in their order.
+func sortedEntries(entries []Entry) []Entry {
+	out := append([]Entry(nil), entries...)
+	end := func(e Entry) Date {
+		if e.End.IsZero() {
+			return e.Start
+		}
+		return e.End
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return newerPeriod(out[i].Start, end(out[i]), out[j].Start, end(out[j]))
+	})
+	return out
+}
+
+func newerPeriod(aStart, aEnd, bStart, bEnd Date) bool {
+	if aEnd != bEnd {
+		return bEnd.Before(aEnd)
+	}
+	return bStart.Before(aStart)
+}
@@ -0,0 +1,679 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Admins get an Edit tab listing the content of every tab: each tab's
+// heading and body, the profile of bio tabs, and the entries, groups and
+// items of the rest, the portfolio's own included. Selecting one opens it in
+// a form, which can preview the tab as it would look and save it to the -tabs
+// content file. A tab that isn't in the file yet, or whose content comes from
+// the portfolio, is written to it in full, seeded with what it shows.
+// The old file is kept beside it with a .bak suffix, and every session picks
+// up the change within contentRefresh.
+//
+// Only the main content file is edited, not those of the locales, and the
+// file is rewritten with sorted keys and two-space indents.
+
+const editTab = "Edit"
+
+// Kinds of edit field.
+const (
+	editLine  = iota // one line of text
+	editList         // a list, edited as one comma-separated line
+	editText         // several lines of text
+	editLines        // a list, edited one item per line
+)
+
+// editField is one field of an edit target: the key it has in the content
+// file, its label and its kind. hint is shown while it is empty.
+type editField struct {
+	key   string
+	label string
+	kind  int
+	hint  string
+}
+
+// The fields of each kind of edit target.
+var (
+	headingFields = []editField{{key: "heading", label: "Heading", kind: editLine}}
+	markdownField = editField{key: "body", label: "Body", kind: editText}
+	bioFields     = []editField{
+		{key: "name", label: "Name", kind: editLine},
+		{key: "role", label: "Role", kind: editLine},
+		{key: "location", label: "Location", kind: editLine},
+		{key: "body", label: "Bio", kind: editText},
+	}
+	entryFields = map[string][]editField{
+		sectionTimeline: {
+			{key: "title", label: "Title", kind: editLine},
+			{key: "subtitle", label: "Subtitle", kind: editLine},
+			{key: "start", label: "Start", kind: editLine, hint: "YYYY-MM"},
+			{key: "end", label: "End", kind: editLine, hint: "YYYY-MM or present"},
+			{key: "period", label: "Period", kind: editLine},
+			{key: "description", label: "Description", kind: editText},
+			{key: "highlights", label: "Highlights", kind: editLines, hint: "One per line"},
+		},
+		sectionCards: {
+			{key: "title", label: "Title", kind: editLine},
+			{key: "description", label: "Description", kind: editText},
+			{key: "tags", label: "Tags", kind: editList, hint: "Comma-separated"},
+			{key: "url", label: "Link", kind: editLine, hint: "https://…"},
+		},
+		sectionTags: {
+			{key: "category", label: "Category", kind: editLine},
+			{key: "skills", label: "Skills", kind: editList, hint: "Comma-separated"},
+		},
+		sectionList: {
+			{key: "label", label: "Label", kind: editLine},
+			{key: "value", label: "Value", kind: editLine},
+		},
+	}
+	// entryLists is the key each tab type keeps its entries under.
+	entryLists = map[string]string{
+		sectionTimeline: "entries",
+		sectionCards:    "entries",
+		sectionTags:     "groups",
+		sectionList:     "items",
+	}
+)
+
+// editTarget is something the Edit tab lists: a tab's settings, or the
+// entry, group or item at index of its list.
+type editTarget struct {
+	tab    string // the tab's title, its key in the content file
+	list   string // "entries", "groups" or "items", or "" for the settings
+	index  int
+	name   string // what the Edit tab lists it as
+	fields []editField
+	values []string // the fields' values as the file has them
+	seed   []byte   // what the tab shows, for what the file leaves out
+}
+
+// editInput is one field of the edit form: a textinput for one-line fields
+// and a textarea for the rest.
+type editInput struct {
+	field editField
+	line  textinput.Model
+	text  textarea.Model
+}
+
+func (in editInput) multiline() bool {
+	return in.field.kind == editText || in.field.kind == editLines
+}
+
+func (in editInput) value() string {
+	if in.multiline() {
+		return in.text.Value()
+	}
+	return in.line.Value()
+}
+
+func (in editInput) view() string {
+	if in.multiline() {
+		return in.text.View()
+	}
+	return in.line.View()
+}
+
+// editor is the Edit tab's state: the targets in the content file as it was
+// last read, and the form for the one being edited, with the file as it was
+// when the form opened. preview is the tab as the form would save it, and
+// previewPortfolio the portfolio, while the form shows it in place of itself.
+type editor struct {
+	targets    []editTarget
+	cursor     int
+	base       []byte // the content file the targets were read from
+	unreadable string // why the content file couldn't be read
+
+	target           editTarget
+	opened           []byte
+	inputs           []editInput
+	focus            int
+	preview          *Tab
+	previewPortfolio portfolio
+	problem          string // why the form couldn't be saved
+	saving           bool
+}
+
+// editSavedMsg reports how saving the edit form went.
+type editSavedMsg struct {
+	err error
+}
+
+// readEdits reads the edit targets from the content file again, keeping the
+// cursor where it was and leaving any open form alone.
+func (m model) readEdits() editor {
+	e := m.edit
+	e.targets, e.base, e.unreadable = nil, nil, ""
+	c := m.app.content
+	if c.path == "" {
+		return e
+	}
+	data, err := c.read()
+	if err == nil {
+		e.targets, err = editTargets(data, c.tabsIn(""))
+	}
+	if err != nil {
+		log.Printf("Could not read %s for editing: %v", c.path, err)
+		e.unreadable = m.locale.T("Could not read the content file.")
+		return e
+	}
+	e.base = data
+	e.cursor = min(e.cursor, max(len(e.targets)-1, 0))
+	return e
+}
+
+// editTargets lists what can be edited in the content file data, with the
+// tabs it makes, shown, in the order they are shown.
+func editTargets(data []byte, shown []Tab) ([]editTarget, error) {
+	raw, err := decodeContent(data)
+	if err != nil {
+		return nil, err
+	}
+	p := portfolioOf(shown)
+	var out []editTarget
+	for _, t := range shown {
+		fields := headingFields
+		switch t.Type {
+		case sectionBio:
+			fields = bioFields
+		case sectionMarkdown:
+			fields = append(fields, markdownField)
+		}
+		var seed []byte
+		if _, ok := raw[t.Title]; !ok || portfolioTabs[t.Type] == t.Title || t.Type == sectionBio && t.Name == "" {
+			if seed, err = json.Marshal(t.withPortfolio(p)); err != nil {
+				return nil, err
+			}
+		}
+		obj, err := seededTab(raw, t.Title, seed)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, newEditTarget(t.Title, "", 0, "Tab settings", fields, obj, seed))
+		list, _ := lookup(obj, entryLists[t.Type]).([]any)
+		for i, v := range list {
+			el, ok := v.(map[string]any)
+			if !ok {
+				continue
+			}
+			fields := entryFields[t.Type]
+			name, _ := lookup(el, fields[0].key).(string)
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			out = append(out, newEditTarget(t.Title, entryLists[t.Type], i, name, fields, el, seed))
+		}
+	}
+	return out, nil
+}
+
+// seededTab is the content file raw's tab title, added to raw if it isn't
+// there, with whatever it leaves out or empty filled in from seed, the tab
+// as it is shown. With a nil seed the tab must be in raw.
+func seededTab(raw map[string]any, title string, seed []byte) (map[string]any, error) {
+	obj, ok := raw[title].(map[string]any)
+	if !ok && raw[title] != nil || !ok && seed == nil {
+		return nil, errContentChanged
+	}
+	if !ok {
+		obj = map[string]any{}
+		raw[title] = obj
+	}
+	if seed == nil {
+		return obj, nil
+	}
+	fill, err := decodeContent(seed)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range fill {
+		if fieldText(editField{}, lookup(obj, k)) == "" {
+			setKey(obj, k, v)
+		}
+	}
+	return obj, nil
+}
+
+func newEditTarget(tab, list string, index int, name string, fields []editField, obj map[string]any, seed []byte) editTarget {
+	t := editTarget{tab: tab, list: list, index: index, name: name, fields: fields, seed: seed}
+	for _, f := range fields {
+		t.values = append(t.values, fieldText(f, lookup(obj, f.key)))
+	}
+	return t
+}
+
+// decodeContent decodes a content file into generic JSON, keeping numbers
+// as they were written. A missing or empty file has no tabs.
+func decodeContent(data []byte) (map[string]any, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return map[string]any{}, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var raw map[string]any
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return raw, nil
+}
+
+// lookup is obj's value for key, matched without regard to case as
+// encoding/json does when it loads the tabs.
+func lookup(obj map[string]any, key string) any {
+	if v, ok := obj[key]; ok {
+		return v
+	}
+	for k, v := range obj {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+// setKey sets obj's key to v, under whatever case the key already has, or
+// deletes it for nil.
+func setKey(obj map[string]any, key string, v any) {
+	for k := range obj {
+		if strings.EqualFold(k, key) {
+			delete(obj, k)
+			if v != nil {
+				obj[k] = v
+			}
+			return
+		}
+	}
+	if v != nil {
+		obj[key] = v
+	}
+}
+
+// fieldText is a JSON value as field f edits it.
+func fieldText(f editField, v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case []any:
+		sep := ", "
+		if f.kind == editLines {
+			sep = "\n"
+		}
+		var parts []string
+		for _, p := range v {
+			parts = append(parts, fmt.Sprint(p))
+		}
+		return strings.Join(parts, sep)
+	}
+	return fmt.Sprint(v)
+}
+
+// fieldValue is field f's text as a JSON value, or nil when it is empty.
+func fieldValue(f editField, text string) any {
+	switch f.kind {
+	case editList, editLines:
+		sep := ","
+		if f.kind == editLines {
+			sep = "\n"
+		}
+		var list []any
+		for _, p := range strings.Split(text, sep) {
+			if p = strings.TrimSpace(p); p != "" {
+				list = append(list, p)
+			}
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		return list
+	case editText:
+		text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	default:
+		text = strings.TrimSpace(text)
+	}
+	if text == "" {
+		return nil
+	}
+	return text
+}
+
+// edited is the content file as the form was opened on, with the form's
+// values in place of the target's.
+func (e editor) edited() ([]byte, error) {
+	raw, err := decodeContent(e.opened)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := seededTab(raw, e.target.tab, e.target.seed)
+	if err != nil {
+		return nil, err
+	}
+	if e.target.list != "" {
+		var ok bool
+		list, _ := lookup(obj, e.target.list).([]any)
+		if e.target.index >= len(list) {
+			return nil, errContentChanged
+		}
+		if obj, ok = list[e.target.index].(map[string]any); !ok {
+			return nil, errContentChanged
+		}
+	}
+	for _, in := range e.inputs {
+		setKey(obj, in.field.key, fieldValue(in.field, in.value()))
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(raw); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// openEditForm opens the selected target in the edit form.
+func (m model) openEditForm() model {
+	e := &m.edit
+	if len(e.targets) == 0 {
+		return m
+	}
+	e.target = e.targets[e.cursor]
+	e.opened = e.base
+	e.inputs = nil
+	e.preview = nil
+	e.problem = ""
+	e.saving = false
+
+	s := m.styles
+	labels := 0
+	areas := 0
+	for _, f := range e.target.fields {
+		labels = max(labels, lipgloss.Width(m.locale.T(f.label)))
+		if f.kind == editText || f.kind == editLines {
+			areas++
+		}
+	}
+	w := min(m.width-16, 72) - labels - 2
+	// Share what the one-line fields, the title, the hint and the box leave
+	// between the text areas.
+	h := 2
+	if areas > 0 {
+		h = max(min((m.viewport.Height-boxFrame-6-len(e.target.fields)+areas)/areas, 8), 1)
+	}
+	for i, f := range e.target.fields {
+		in := editInput{field: f}
+		if in.multiline() {
+			in.text = s.textArea(m.locale.T(f.hint), 0, w, h)
+			in.text.SetValue(e.target.values[i])
+			in.text.CursorStart()
+			for in.text.Line() > 0 {
+				in.text.CursorUp()
+			}
+		} else {
+			in.line = s.textInput(m.locale.T(f.hint), 0, w)
+			in.line.SetValue(e.target.values[i])
+		}
+		e.inputs = append(e.inputs, in)
+	}
+	e.setFocus(0)
+	return m.setOverlay(editOverlay)
+}
+
+// setFocus moves the keyboard to input i.
+func (e *editor) setFocus(i int) {
+	e.focus = i
+	for j := range e.inputs {
+		in := &e.inputs[j]
+		switch {
+		case in.multiline() && j == i:
+			in.text.Focus()
+		case in.multiline():
+			in.text.Blur()
+		case j == i:
+			in.line.Focus()
+		default:
+			in.line.Blur()
+		}
+	}
+}
+
+// updateEditForm handles keys while the edit form is open: the field keys
+// move between fields, select moves on from a one-line field, preview
+// switches between the form and the tab it would save, send saves and close
+// cancels, or goes back to the form from the preview.
+func (m model) updateEditForm(msg tea.KeyMsg) (model, tea.Cmd) {
+	e := &m.edit
+	n := len(e.inputs)
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return m, tea.Quit
+	case e.saving:
+		return m, nil
+	case key.Matches(msg, m.keys.Close):
+		if e.preview != nil {
+			return m.setPreview(nil), nil
+		}
+		return m.setOverlay(noOverlay), nil
+	case key.Matches(msg, m.keys.Preview):
+		if e.preview != nil {
+			return m.setPreview(nil), nil
+		}
+		t, p, err := e.previewTab(m.app.content.path)
+		if err != nil {
+			e.problem = err.Error()
+			return m, nil
+		}
+		m.edit.previewPortfolio = p
+		return m.setPreview(&t), nil
+	case key.Matches(msg, m.keys.Send):
+		data, err := e.edited()
+		if err == nil {
+			_, err = parseTabs(m.app.content.path, data)
+		}
+		if err != nil {
+			e.problem = m.editProblem(err)
+			return m, nil
+		}
+		e.problem = ""
+		e.saving = true
+		c, base := m.app.content, e.opened
+		return m, func() tea.Msg { return editSavedMsg{err: c.save(base, data)} }
+	case e.preview != nil:
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	case key.Matches(msg, m.keys.NextField), key.Matches(msg, m.keys.Select) && !e.inputs[e.focus].multiline():
+		e.setFocus((e.focus + 1) % n)
+		return m, nil
+	case key.Matches(msg, m.keys.PrevField):
+		e.setFocus((e.focus - 1 + n) % n)
+		return m, nil
+	}
+	var cmd tea.Cmd
+	in := &e.inputs[e.focus]
+	if in.multiline() {
+		in.text, cmd = in.text.Update(msg)
+	} else {
+		in.line, cmd = in.line.Update(msg)
+	}
+	e.problem = ""
+	return m, cmd
+}
+
+// previewTab is the tab the form would save, and the portfolio it would
+// show.
+func (e editor) previewTab(path string) (Tab, portfolio, error) {
+	data, err := e.edited()
+	if err != nil {
+		return Tab{}, portfolio{}, err
+	}
+	tabs, err := parseTabs(path, data)
+	if err != nil {
+		return Tab{}, portfolio{}, err
+	}
+	for _, t := range tabs {
+		if t.Title == e.target.tab {
+			return t, portfolioOf(tabs), nil
+		}
+	}
+	return Tab{}, portfolio{}, errContentChanged
+}
+
+// setPreview shows tab t in place of the edit form, or the form again for
+// nil.
+func (m model) setPreview(t *Tab) model {
+	m.edit.preview = t
+	m.edit.problem = ""
+	m.setContent()
+	m.viewport.GotoTop()
+	return m
+}
+
+// editProblem says why the form couldn't be saved.
+func (m model) editProblem(err error) string {
+	if errors.Is(err, errContentChanged) {
+		return m.locale.T("The content file changed since you opened this. Cancel and try again.")
+	}
+	return err.Error()
+}
+
+// editSaved closes the form once it is saved, picking up the new content
+// straight away rather than at the next refresh, or says why it wasn't.
+func (m model) editSaved(msg editSavedMsg) (model, tea.Cmd) {
+	m.edit.saving = false
+	if msg.err != nil {
+		log.Printf("Could not save %s: %v", m.app.content.path, msg.err)
+		m.edit.problem = m.editProblem(msg.err)
+		return m, nil
+	}
+	m.edit.preview = nil
+	m = m.setOverlay(noOverlay)
+	m.content = m.app.content.current()
+	m = m.setTabs()
+	name := filepath.Base(m.app.content.path)
+	if len(m.edit.opened) == 0 {
+		return m.setNotice(m.locale.F("Saved %s.", name))
+	}
+	return m.setNotice(m.locale.F("Saved %s; the old version is in %s.", name, name+".bak"))
+}
+
+// followEditCursor scrolls the Edit tab so the selected target is in view.
+func (m *model) followEditCursor() {
+	for _, z := range m.zones {
+		if z.kind != zoneEdit || z.index != m.edit.cursor {
+			continue
+		}
+		switch {
+		case z.rect.y < m.viewport.YOffset:
+			m.viewport.SetYOffset(z.rect.y)
+		case z.rect.y >= m.viewport.YOffset+m.viewport.Height:
+			m.viewport.SetYOffset(z.rect.y - m.viewport.Height + 1)
+		}
+	}
+}
+
+// editHint says how to use the Edit tab, or why it can't be used.
+func (m model) editHint() string {
+	switch {
+	case m.app.content.path == "":
+		return m.locale.T("Start the server with -tabs to edit content from here.")
+	case m.edit.unreadable != "":
+		return m.edit.unreadable
+	case m.accessible:
+		return m.locale.T("Editing needs the full layout.")
+	}
+	return m.locale.F("Press %s to edit the selected entry. Saving keeps the old file as %s and updates every session.",
+		m.keys.Select.Help().Key, filepath.Base(m.app.content.path)+".bak")
+}
+
+// renderEditList renders the Edit tab: the edit targets under their tabs'
+// titles, with the selected one marked.
+func renderEditList(s styles, width int, targets []editTarget, cursor int, hint string) string {
+	var b strings.Builder
+	contentWidth := min(width-4, 72)
+
+	b.WriteString(s.sectionHeader.Render(s.l.T("Edit content")))
+	b.WriteString("\n\n")
+	b.WriteString(s.r.NewStyle().Width(contentWidth).Foreground(s.theme.Muted).Render(hint))
+	b.WriteString("\n")
+
+	for i, t := range targets {
+		if i == 0 || t.tab != targets[i-1].tab {
+			b.WriteString("\n" + s.secondaryText.Bold(true).Render(s.l.T(t.tab)) + "\n")
+		}
+		name := t.name
+		if t.list == "" {
+			name = s.l.T(name)
+		}
+		marker, style := "  ", s.mutedText
+		if i == cursor {
+			marker, style = s.accentText.Render(s.g.Bullet+" "), s.accentText
+		}
+		b.WriteString("  " + markZone(zoneEdit, i, marker+style.Render(truncate(name, contentWidth-4, s.g.Ellipsis))) + "\n")
+	}
+	return b.String()
+}
+
+// renderEditForm is the box the edit form is drawn in, each field beside
+// its label.
+func (s styles) renderEditForm(e editor, hint string, width, height int) string {
+	labelWidth := 0
+	for _, in := range e.inputs {
+		labelWidth = max(labelWidth, lipgloss.Width(s.l.T(in.field.label)))
+	}
+	name := e.target.name
+	if e.target.list == "" {
+		name = s.l.T(name)
+	}
+	title := s.l.T(e.target.tab) + " " + s.g.Bullet + " " + name
+	rows := []string{s.accentText.Render(truncate(title, min(width-16, 72), s.g.Ellipsis)), ""}
+	for i, in := range e.inputs {
+		label := s.dimText
+		if i == e.focus {
+			label = s.secondaryText
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, label.Width(labelWidth+2).Render(s.l.T(in.field.label)), in.view()))
+	}
+	switch {
+	case e.saving:
+		rows = append(rows, "", s.dimText.Render(s.l.T("Saving…")))
+	case e.problem != "":
+		rows = append(rows, "", s.r.NewStyle().Foreground(s.theme.Pink).Width(min(width-16, 72)).Render(e.problem))
+	}
+	rows = append(rows, "", hint)
+	return s.placeBox(lipgloss.JoinVertical(lipgloss.Left, rows...), width, height)
+}
+
+// plainEdits reads out the Edit tab.
+func plainEdits(l *locale, targets []editTarget, hint string) string {
+	var b strings.Builder
+	for i, t := range targets {
+		if i == 0 || t.tab != targets[i-1].tab {
+			b.WriteString(l.T(t.tab) + ":\n")
+		}
+		name := t.name
+		if t.list == "" {
+			name = l.T(name)
+		}
+		b.WriteString("- " + name + "\n")
+	}
+	return b.String() + "\n" + hint + "\n"
+}
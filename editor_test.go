@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// findTarget is the target named name in tab, or fails the test.
+func findTarget(t *testing.T, targets []editTarget, tab, name string) editTarget {
+	t.Helper()
+	for _, tt := range targets {
+		if tt.tab == tab && tt.name == name {
+			return tt
+		}
+	}
+	t.Fatalf("no target %q in tab %q", name, tab)
+	return editTarget{}
+}
+
+// formFor is an editor with target's form open on the content file data, as
+// if its fields were filled in with values.
+func formFor(data []byte, target editTarget, values []string) editor {
+	e := editor{target: target, opened: data}
+	for i, f := range target.fields {
+		in := editInput{field: f, line: textinput.New(), text: textarea.New()}
+		if in.multiline() {
+			in.text.SetValue(values[i])
+		} else {
+			in.line.SetValue(values[i])
+		}
+		e.inputs = append(e.inputs, in)
+	}
+	return e
+}
+
+func TestEditBuiltinContent(t *testing.T) {
+	targets, err := editTargets(nil, builtinTabs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	about := findTarget(t, targets, portfolioTabs[sectionBio], "Tab settings")
+	if about.values[0] != profile.Name {
+		t.Errorf("About lists name %q, want %q", about.values[0], profile.Name)
+	}
+
+	job := findTarget(t, targets, portfolioTabs[sectionTimeline], experiences[0].Title)
+	values := append([]string(nil), job.values...)
+	values[0] = "Staff Engineer"
+	data, err := formFor(nil, job, values).edited()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tabs, err := parseTabs("tabs.json", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tabs) != len(builtinTabs) {
+		t.Errorf("got %d tabs, want %d", len(tabs), len(builtinTabs))
+	}
+	p := portfolioOf(tabs)
+	if len(p.experiences) != len(experiences) {
+		t.Fatalf("got %d experiences, want %d", len(p.experiences), len(experiences))
+	}
+	if got := p.experiences[0]; got.Title != "Staff Engineer" || got.Company != experiences[0].Company {
+		t.Errorf("edited experience is %s at %s", got.Title, got.Company)
+	}
+	if p.profile.Name != profile.Name || len(p.projects) != len(projects) {
+		t.Error("editing an experience changed the rest of the portfolio")
+	}
+}
+
+func TestEditSeedsWhatTheFileLeavesOut(t *testing.T) {
+	data := []byte(`{"Experience": {"type": "timeline", "heading": "Work"}}`)
+	tabs, err := parseTabs("tabs.json", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets, err := editTargets(data, tabs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings := findTarget(t, targets, "Experience", "Tab settings")
+	if settings.values[0] != "Work" {
+		t.Errorf("heading is %q, want the file's", settings.values[0])
+	}
+	findTarget(t, targets, "Experience", experiences[len(experiences)-1].Title)
+}
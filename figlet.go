@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bufio"
+	"embed"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The banner is rendered from the profile name with a FIGlet font, so it
+// follows the name instead of being drawn by hand. Fonts use the standard
+// .flf format; a few are bundled and -font can point at any other.
+
+//go:embed fonts/*.flf
+var fontFiles embed.FS
+
+// defaultFont is the bundled font the banner uses unless -font says otherwise.
+const defaultFont = "standard"
+
+// Horizontal layout bits of an .flf header's full_layout field: the six
+// controlled smushing rules, then fitting and smushing themselves.
+const (
+	smushEqual     = 1 << iota // identical characters merge
+	smushUnderline             // an underscore gives way to a border character
+	smushHierarchy             // of two border characters, the later class wins
+	smushPair                  // opposing brackets become a bar
+	smushBigX                  // /\ becomes |, \/ becomes Y and >< becomes X
+	smushHardblank             // two hardblanks merge
+	layoutFit                  // characters are moved together until they touch
+	layoutSmush                // and then one column further, merging by the rules
+)
+
+// figFont is a parsed FIGlet font.
+type figFont struct {
+	name      string
+	height    int
+	hardblank rune
+	layout    int
+	glyphs    map[rune][]string
+}
+
+// parseFont reads an .flf font. Only the required ASCII characters are read;
+// code-tagged characters after them are ignored.
+func parseFont(name string, r io.Reader) (*figFont, error) {
+	sc := bufio.NewScanner(r)
+	if !sc.Scan() {
+		return nil, fmt.Errorf("%s: empty font", name)
+	}
+	header := strings.Fields(sc.Text())
+	if len(header) < 6 || !strings.HasPrefix(header[0], "flf2a") || len(header[0]) < 6 {
+		return nil, fmt.Errorf("%s: not a FIGlet font", name)
+	}
+	var nums []int
+	for _, field := range header[1:] {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("%s: bad header field %q", name, field)
+		}
+		nums = append(nums, n)
+	}
+	f := &figFont{
+		name:      name,
+		height:    nums[0],
+		hardblank: []rune(header[0])[5],
+		glyphs:    map[rune][]string{},
+	}
+	if f.height < 1 {
+		return nil, fmt.Errorf("%s: bad height %d", name, f.height)
+	}
+	switch oldLayout := nums[3]; {
+	case len(nums) > 6:
+		f.layout = nums[6] & 0xff
+	case oldLayout < 0:
+		f.layout = 0
+	case oldLayout == 0:
+		f.layout = layoutFit
+	default:
+		f.layout = oldLayout&0x1f | layoutSmush
+	}
+
+	for range nums[4] {
+		sc.Scan()
+	}
+	for c := ' '; c <= '~'; c++ {
+		rows := make([]string, 0, f.height)
+		for range f.height {
+			if !sc.Scan() {
+				if c == ' ' {
+					return nil, fmt.Errorf("%s: no characters", name)
+				}
+				return f, sc.Err() // missing trailing characters are tolerated
+			}
+			line := strings.TrimRight(sc.Text(), " \t\r")
+			if line != "" {
+				// The endmark is whatever the line ends with, once or twice.
+				end := line[len(line)-1:]
+				line = strings.TrimSuffix(strings.TrimSuffix(line, end), end)
+			}
+			rows = append(rows, line)
+		}
+		f.glyphs[c] = padRows(rows)
+	}
+	return f, sc.Err()
+}
+
+// padRows pads a glyph's rows to the same width.
+func padRows(rows []string) []string {
+	w := 0
+	for _, row := range rows {
+		w = max(w, len([]rune(row)))
+	}
+	for i, row := range rows {
+		rows[i] = row + strings.Repeat(" ", w-len([]rune(row)))
+	}
+	return rows
+}
+
+// bundledFonts returns the embedded fonts, tallest first.
+func bundledFonts() []*figFont {
+	entries, _ := fontFiles.ReadDir("fonts")
+	var fonts []*figFont
+	for _, e := range entries {
+		data, err := fontFiles.Open("fonts/" + e.Name())
+		if err != nil {
+			continue
+		}
+		f, err := parseFont(strings.TrimSuffix(e.Name(), ".flf"), data)
+		data.Close()
+		if err != nil {
+			panic(err) // a bundled font is broken
+		}
+		fonts = append(fonts, f)
+	}
+	sort.SliceStable(fonts, func(i, j int) bool { return fonts[i].height > fonts[j].height })
+	return fonts
+}
+
+// bannerFonts returns the fonts to try for the banner, largest first: the
+// font named by spec, either bundled or a path to an .flf file, then the
+// bundled fonts shorter than it.
+func bannerFonts(spec string) ([]*figFont, error) {
+	bundled := bundledFonts()
+	var first *figFont
+	for _, f := range bundled {
+		if f.name == spec {
+			first = f
+		}
+	}
+	if first == nil {
+		file, err := os.Open(spec)
+		if err != nil {
+			return nil, fmt.Errorf("unknown font %q", spec)
+		}
+		defer file.Close()
+		if first, err = parseFont(strings.TrimSuffix(filepath.Base(spec), ".flf"), file); err != nil {
+			return nil, err
+		}
+	}
+
+	fonts := []*figFont{first}
+	for _, f := range bundled {
+		if f != first && f.height < first.height {
+			fonts = append(fonts, f)
+		}
+	}
+	return fonts, nil
+}
+
+// render sets text in the font. It fails if the font lacks a character.
+func (f *figFont) render(text string) ([]string, bool) {
+	out := make([]string, f.height)
+	prevWidth := 0
+	for _, c := range text {
+		glyph, ok := f.glyphs[c]
+		if !ok {
+			return nil, false
+		}
+		out = f.add(out, glyph, prevWidth)
+		prevWidth = len([]rune(glyph[0]))
+	}
+	for i, row := range out {
+		out[i] = strings.TrimRight(strings.ReplaceAll(row, string(f.hardblank), " "), " ")
+	}
+	return out, true
+}
+
+// add appends a glyph to the rows rendered so far, moving it left as far as
+// the font's layout allows, as FIGlet does.
+func (f *figFont) add(out, glyph []string, prevWidth int) []string {
+	width := len([]rune(glyph[0]))
+	amount := f.overlap(out, glyph, prevWidth, width)
+	for row := range out {
+		line, char := []rune(out[row]), []rune(glyph[row])
+		for k := range amount {
+			if col := len(line) - amount + k; col >= 0 {
+				line[col] = f.smush(line[col], char[k], prevWidth, width)
+			}
+		}
+		out[row] = string(line) + string(char[amount:])
+	}
+	return out
+}
+
+// overlap is how many columns a glyph can move into the rows rendered so
+// far: up to the point where some row's characters would touch, and one
+// column further wherever every touching pair smushes.
+func (f *figFont) overlap(out, glyph []string, prevWidth, width int) int {
+	if f.layout&(layoutFit|layoutSmush) == 0 {
+		return 0
+	}
+	amount := width
+	for row := range out {
+		line, char := []rune(out[row]), []rune(glyph[row])
+
+		end := len(line) - 1 // the line's last visible character
+		for end > 0 && line[end] == ' ' {
+			end--
+		}
+		start := 0 // the glyph's first visible character
+		for start < len(char) && char[start] == ' ' {
+			start++
+		}
+
+		blank := end < 0 || line[end] == ' '
+		end = max(end, 0)
+
+		n := start + len(line) - 1 - end
+		switch {
+		case blank:
+			n++
+		case start < len(char) && f.smush(line[end], char[start], prevWidth, width) != 0:
+			n++
+		}
+		amount = min(amount, n)
+	}
+	return amount
+}
+
+// smush merges two characters by the font's rules, returning 0 if they do not
+// merge.
+func (f *figFont) smush(l, r rune, prevWidth, width int) rune {
+	switch {
+	case l == ' ':
+		return r
+	case r == ' ':
+		return l
+	case prevWidth < 2 || width < 2, f.layout&layoutSmush == 0:
+		return 0
+	}
+
+	if f.layout&0x3f == 0 {
+		// Universal smushing: the later character wins over all but a hardblank.
+		if r == f.hardblank {
+			return l
+		}
+		return r
+	}
+
+	if l == f.hardblank || r == f.hardblank {
+		if l == r && f.layout&smushHardblank != 0 {
+			return l
+		}
+		return 0
+	}
+	if f.layout&smushEqual != 0 && l == r {
+		return l
+	}
+	if f.layout&smushUnderline != 0 {
+		const borders = `|/\[]{}()<>`
+		if l == '_' && strings.ContainsRune(borders, r) {
+			return r
+		}
+		if r == '_' && strings.ContainsRune(borders, l) {
+			return l
+		}
+	}
+	if f.layout&smushHierarchy != 0 {
+		classes := []string{"|", `/\`, "[]", "{}", "()", "<>"}
+		rank := func(c rune) int {
+			for i, class := range classes {
+				if strings.ContainsRune(class, c) {
+					return i
+				}
+			}
+			return -1
+		}
+		if lr, rr := rank(l), rank(r); lr >= 0 && rr >= 0 && lr != rr {
+			if lr > rr {
+				return l
+			}
+			return r
+		}
+	}
+	if f.layout&smushPair != 0 {
+		switch string([]rune{l, r}) {
+		case "[]", "][", "{}", "}{", "()", ")(":
+			return '|'
+		}
+	}
+	if f.layout&smushBigX != 0 {
+		switch string([]rune{l, r}) {
+		case `/\`:
+			return '|'
+		case `\/`:
+			return 'Y'
+		case "><":
+			return 'X'
+		}
+	}
+	return 0
+}
+
+// banner sets text in the first font in which it fits in width cells,
+// breaking it between words onto several banners if no font fits it on one.
+// It returns nil if the text fits in none of the fonts.
+func banner(fonts []*figFont, text string, width int) []string {
+	for _, f := range fonts {
+		if lines, ok := f.render(text); ok && linesWidth(lines) <= width {
+			return trimBlankLines(lines)
+		}
+	}
+	words := strings.Fields(text)
+	for _, f := range fonts {
+		if lines, ok := f.wrap(words, width); ok {
+			return lines
+		}
+	}
+	return nil
+}
+
+// wrap sets words in the font, as many to a banner as fit in width cells,
+// with a blank line between banners.
+func (f *figFont) wrap(words []string, width int) ([]string, bool) {
+	var lines []string
+	for len(words) > 0 {
+		n := 1
+		for n < len(words) {
+			if next, ok := f.render(strings.Join(words[:n+1], " ")); !ok || linesWidth(next) > width {
+				break
+			}
+			n++
+		}
+		rows, ok := f.render(strings.Join(words[:n], " "))
+		if !ok || linesWidth(rows) > width {
+			return nil, false
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, trimBlankLines(rows)...)
+		words = words[n:]
+	}
+	return lines, true
+}
+
+func linesWidth(lines []string) int {
+	w := 0
+	for _, line := range lines {
+		w = max(w, len([]rune(line)))
+	}
+	return w
+}
+
+// trimBlankLines drops the empty lines a font leaves above and below text
+// without ascenders or descenders.
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// glyphs are the symbols the UI draws with. unicodeGlyphs is the default;
+// asciiGlyphs is for terminals and fonts that can't show the rest, such as
+// Windows conhost and serial consoles, where they would misalign the layout.
+// Each ASCII glyph is as wide as the one it replaces.
+type glyphs struct {
+	Name      string
+	Separator string // between role and location on About
+	Marker    string // a role on the experience rail
+	Rail      string
+	RailEnd   string
+	Bullet    string
+	Card      string // project name marker
+	Arrow     string
+	ScrollL   string // tab bar overflow
+	ScrollR   string
+	Square    string // skill category marker
+	Dot       string // dotted rules
+	Graduate  string // two cells wide
+	Wave      string // two cells wide
+	Block     string // timeline bars and theme swatches
+	Grid      string // timeline year lines
+	Tick      string // timeline axis ticks
+	Axis      string
+	Ellipsis  string
+	Bull      string          // footer hint separator
+	Copyright string          // may be wider than ©
+	Border    lipgloss.Border // boxes and cards
+	Rule      lipgloss.Border // the line under the tab bar
+	Contact   map[string]string
+
+	// text maps typographic punctuation and arrows in content and key help to
+	// ASCII lookalikes. It is nil for unicodeGlyphs.
+	text *strings.Replacer
+}
+
+var unicodeGlyphs = glyphs{
+	Name:      "unicode",
+	Separator: "◆",
+	Marker:    "●",
+	Rail:      "│",
+	RailEnd:   "╵",
+	Bullet:    "▸",
+	Card:      "◈",
+	Arrow:     "→",
+	ScrollL:   "‹",
+	ScrollR:   "›",
+	Square:    "■",
+	Dot:       "·",
+	Graduate:  "🎓",
+	Wave:      "👋",
+	Block:     "█",
+	Grid:      "┊",
+	Tick:      "┬",
+	Axis:      "─",
+	Ellipsis:  "…",
+	Bull:      "•",
+	Copyright: "©",
+	Border:    lipgloss.RoundedBorder(),
+	Rule:      lipgloss.NormalBorder(),
+	Contact: map[string]string{
+		"Email": "✉", "Phone": "☎", "Office": "☏",
+		"Portfolio": "⌂", "GitHub": "◆", "LinkedIn": "∞", "Location": "◉",
+	},
+}
+
+var asciiGlyphs = glyphs{
+	Name:      "ascii",
+	Separator: "*",
+	Marker:    "o",
+	Rail:      "|",
+	RailEnd:   "'",
+	Bullet:    ">",
+	Card:      "#",
+	Arrow:     ">",
+	ScrollL:   "<",
+	ScrollR:   ">",
+	Square:    "#",
+	Dot:       ".",
+	Graduate:  ">>",
+	Wave:      "o/",
+	Block:     "#",
+	Grid:      ":",
+	Tick:      "+",
+	Axis:      "-",
+	Ellipsis:  "~",
+	Bull:      "*",
+	Copyright: "(c)",
+	Border:    lipgloss.ASCIIBorder(),
+	Rule:      lipgloss.ASCIIBorder(),
+	Contact: map[string]string{
+		"Email": "@", "Phone": "#", "Office": "#",
+		"Portfolio": "~", "GitHub": "*", "LinkedIn": "+", "Location": "o",
+	},
+	text: strings.NewReplacer(
+		"—", "-", "–", "-", "‘", "'", "’", "'", "“", `"`, "”", `"`,
+		"·", ".", "•", "*", "←", "<", "→", ">", "↑", "^", "↓", "v", "½", "1/2", "×", "x",
+	),
+}
+
+// contactIcon is the icon for a contact label, or an arrow for unknown ones.
+func (g glyphs) contactIcon(label string) string {
+	if icon, ok := g.Contact[label]; ok {
+		return icon
+	}
+	return g.Arrow
+}
+
+// Text converts s to characters the glyph set can display.
+func (g glyphs) Text(s string) string {
+	if g.text == nil {
+		return s
+	}
+	return g.text.Replace(s)
+}
+
+// asciiTerms are terminals that can't be relied on to draw anything beyond
+// ASCII.
+var asciiTerms = map[string]bool{
+	"dumb": true, "vt52": true, "vt100": true, "vt102": true, "vt220": true,
+	"ansi": true, "cons25": true, "pcansi": true,
+}
+
+// detectGlyphs picks the glyph set for a client from the TERM and locale it
+// sent. Unicode is assumed unless TERM names an ASCII-only terminal or the
+// locale explicitly uses a charset other than UTF-8.
+func detectGlyphs(term string, environ []string) glyphs {
+	if asciiTerms[term] {
+		return asciiGlyphs
+	}
+
+	env := map[string]string{}
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	// The first of these that is set decides, as in setlocale(3).
+	for _, k := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := env[k]
+		if locale == "" {
+			continue
+		}
+		l := strings.ToLower(locale)
+		if !strings.Contains(l, "utf-8") && !strings.Contains(l, "utf8") {
+			return asciiGlyphs
+		}
+		break
+	}
+	return unicodeGlyphs
+}
+
+// findGlyphs returns the glyph set with the given name.
+func findGlyphs(name string) (glyphs, bool) {
+	switch name {
+	case unicodeGlyphs.Name:
+		return unicodeGlyphs, true
+	case asciiGlyphs.Name:
+		return asciiGlyphs, true
+	}
+	return glyphs{}, false
+}
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"log"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The Guestbook tab lists short messages left by visitors, newest first,
+// a page at a time. Signing needs a public key: each entry shows the SSH user
+// name and key fingerprint it was signed with. A key can sign once every
+// guestbookEvery, and an IP address guestbookPerIP times. Admins can hide,
+// show and delete entries. Sessions pick up entries signed elsewhere every
+// guestbookRefresh, from the store's cached copy.
+
+const guestbookTab = "Guestbook"
+
+const (
+	guestbookPageSize = 8
+	guestbookMaxLen   = 200 // characters in a message
+	guestbookMaxUser  = 32  // characters of the user name kept
+	guestbookEvery    = time.Hour
+	guestbookPerIP    = 5 // signings from one IP address every guestbookEvery
+	guestbookRefresh  = 5 * time.Second
+)
+
+// blockedWords are words a guestbook message may not contain.
+var blockedWords = map[string]bool{
+	"fuck": true, "fucking": true, "shit": true, "cunt": true, "bitch": true,
+	"asshole": true, "bastard": true, "dick": true, "faggot": true, "nigger": true,
+	"retard": true, "slut": true, "whore": true, "wanker": true,
+}
+
+// guestbookEntry is one signed message. ID is its key in the store.
+type guestbookEntry struct {
+	ID          uint64    `json:"-"`
+	User        string    `json:"user"`
+	Fingerprint string    `json:"fingerprint"`
+	Message     string    `json:"message"`
+	Time        time.Time `json:"time"`
+	Hidden      bool      `json:"hidden,omitempty"`
+}
+
+// guestbookMsg carries the guestbook as it now stands. tick is set when it
+// comes from the refresh timer, which it re-arms.
+type guestbookMsg struct {
+	entries []guestbookEntry
+	tick    bool
+}
+
+// signedMsg reports how signing went: wait is how long until the key may
+// sign again if it signed too recently.
+type signedMsg struct {
+	entries []guestbookEntry
+	wait    time.Duration
+	err     error
+}
+
+// readGuestbook loads the guestbook, logging and leaving it empty on error.
+func readGuestbook(st *store) []guestbookEntry {
+	entries, err := st.guestbook()
+	if err != nil {
+		log.Printf("Could not read guestbook: %v", err)
+	}
+	return entries
+}
+
+// watchGuestbook reads the guestbook again after guestbookRefresh.
+func (m model) watchGuestbook() tea.Cmd {
+	if !m.app.guestbook {
+		return nil
+	}
+	st := m.app.store
+	return tea.Tick(guestbookRefresh, func(time.Time) tea.Msg {
+		return guestbookMsg{entries: readGuestbook(st), tick: true}
+	})
+}
+
+// setGuestbook replaces the guestbook entries, keeping the cursor in range.
+func (m model) setGuestbook(entries []guestbookEntry) model {
+	if reflect.DeepEqual(entries, m.guestbook) {
+		return m
+	}
+	m.guestbook = entries
+	m.guestCursor = min(m.guestCursor, max(len(m.guestbookEntries())-1, 0))
+	if m.ready && m.tabs[m.activeTab].Type == sectionGuestbook {
+		m.setContent()
+	}
+	return m
+}
+
+// guestbookEntries is the entries the visitor can see: all of them for
+// admins, and the ones not hidden for everyone else.
+func (m model) guestbookEntries() []guestbookEntry {
+	if m.visitor.admin {
+		return m.guestbook
+	}
+	var out []guestbookEntry
+	for _, e := range m.guestbook {
+		if !e.Hidden {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// stepGuestbook moves to the next or previous entry for admins, who select
+// entries to moderate, and to the next or previous page for everyone else.
+func (m model) stepGuestbook(next bool) model {
+	n := len(m.guestbookEntries())
+	if n == 0 {
+		return m
+	}
+	if m.visitor.admin {
+		if next {
+			m.guestCursor = (m.guestCursor + 1) % n
+		} else {
+			m.guestCursor = (m.guestCursor - 1 + n) % n
+		}
+		return m
+	}
+	pages := (n + guestbookPageSize - 1) / guestbookPageSize
+	page := m.guestCursor / guestbookPageSize
+	if next {
+		page = (page + 1) % pages
+	} else {
+		page = (page - 1 + pages) % pages
+	}
+	m.guestCursor = page * guestbookPageSize
+	return m
+}
+
+// openSignForm opens the form for writing a guestbook entry.
+func (m model) openSignForm() model {
+	in := m.styles.textInput(m.locale.T("Say hello…"), guestbookMaxLen, min(m.width-16, 60))
+	in.Focus()
+	m.signInput = in
+	m.signProblem = ""
+	return m.setOverlay(signOverlay)
+}
+
+// updateSignForm handles keys while the sign form is open: select signs,
+// close cancels and everything else edits the message.
+func (m model) updateSignForm(msg tea.KeyMsg) (model, tea.Cmd) {
+	switch {
+	case msg.Type == tea.KeyCtrlC:
+		return m, tea.Quit
+	case key.Matches(msg, m.keys.Close):
+		m = m.setOverlay(noOverlay)
+		if m.accessible {
+			return m, tea.Println(m.locale.T("Signing cancelled."))
+		}
+		return m, nil
+	case key.Matches(msg, m.keys.Select):
+		text := cleanText(m.signInput.Value())
+		if p := signProblem(text); p != "" {
+			m.signProblem = m.locale.T(p)
+			if m.accessible {
+				return m, tea.Println(m.signProblem)
+			}
+			return m, nil
+		}
+		st, limit, ip := m.app.store, m.app.signings, m.visitor.ip
+		e := guestbookEntry{
+			User:        truncate(cleanText(m.visitor.user), guestbookMaxUser, "…"),
+			Fingerprint: m.visitor.fingerprint,
+			Message:     text,
+			Time:        time.Now().UTC(),
+		}
+		return m, func() tea.Msg { return signGuestbook(st, limit, ip, e) }
+	}
+	var cmd tea.Cmd
+	m.signInput, cmd = m.signInput.Update(msg)
+	m.signProblem = ""
+	return m, cmd
+}
+
+// signGuestbook adds e to the guestbook, if neither ip nor e's key has
+// signed too often. A signing that doesn't go in doesn't count against ip.
+func signGuestbook(st *store, limit *throttle, ip string, e guestbookEntry) signedMsg {
+	if wait := limit.take(ip, e.Time); wait > 0 {
+		return signedMsg{wait: wait}
+	}
+	wait, err := st.sign(e, guestbookEvery)
+	if wait > 0 || err != nil {
+		limit.refund(ip, e.Time)
+	}
+	return signedMsg{entries: readGuestbook(st), wait: wait, err: err}
+}
+
+// signed closes the sign form once the entry is in, or says why it isn't.
+func (m model) signed(msg signedMsg) (model, tea.Cmd) {
+	switch {
+	case msg.err != nil:
+		log.Printf("Could not sign guestbook: %v", msg.err)
+		m.signProblem = m.locale.T("Something went wrong. Please try again later.")
+	case msg.wait > 0:
+		m.signProblem = m.locale.F("You can sign again in %d min.", int(math.Ceil(msg.wait.Minutes())))
+	default:
+		m = m.setOverlay(noOverlay)
+		m.guestCursor = 0
+		m = m.setGuestbook(msg.entries)
+		m.setContent()
+		m.viewport.GotoTop()
+		if m.accessible {
+			return m, tea.Println(m.locale.T("Thanks for signing!") + "\n" + m.plainTab(m.activeTab))
+		}
+		return m.setNotice(m.locale.T("Thanks for signing!"))
+	}
+	if m.accessible {
+		return m, tea.Println(m.signProblem)
+	}
+	return m, nil
+}
+
+// moderate hides or shows the selected entry, or deletes it.
+func (m model) moderate(remove bool) (model, tea.Cmd) {
+	entries := m.guestbookEntries()
+	if !m.visitor.admin || len(entries) == 0 {
+		return m, nil
+	}
+	e := entries[m.guestCursor]
+	st := m.app.store
+	notice := "Entry deleted"
+	switch {
+	case !remove && e.Hidden:
+		notice = "Entry shown"
+	case !remove:
+		notice = "Entry hidden"
+	}
+	m, cmd := m.setNotice(m.locale.T(notice))
+	return m, tea.Batch(cmd, func() tea.Msg {
+		var err error
+		if remove {
+			err = st.deleteEntry(e.ID)
+		} else {
+			err = st.setHidden(e.ID, !e.Hidden)
+		}
+		if err != nil {
+			log.Printf("Could not moderate guestbook: %v", err)
+		}
+		return guestbookMsg{entries: readGuestbook(st)}
+	})
+}
+
+// cleanText keeps a visitor's text to one line of printable characters, so
+// it can't move the cursor, restyle or reorder what other visitors see.
+func cleanText(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r) {
+			return ' '
+		}
+		return r
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// signProblem is why a message can't go in the guestbook, or "" if it can.
+func signProblem(text string) string {
+	if text == "" {
+		return "Write a message first."
+	}
+	if len([]rune(text)) > guestbookMaxLen {
+		return "That message is too long."
+	}
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, w := range words {
+		if blockedWords[w] {
+			return "Please keep it friendly."
+		}
+	}
+	return ""
+}
+
+// shortFingerprint is enough of a key fingerprint to tell keys apart.
+func shortFingerprint(fp string) string {
+	return truncate(fp, len("SHA256:")+10, "…")
+}
+
+// renderGuestbook renders a page of entries: the page with the cursor on it,
+// with the cursor's entry marked for admins.
+func renderGuestbook(s styles, width int, entries []guestbookEntry, cursor int, admin bool, signHint string) string {
+	var b strings.Builder
+	contentWidth := min(width-4, 72)
+
+	b.WriteString(s.sectionHeader.Render(s.l.T(guestbookTab)))
+	b.WriteString("\n\n")
+	b.WriteString(s.r.NewStyle().Width(contentWidth).Foreground(s.theme.Muted).Render(signHint))
+	b.WriteString("\n\n")
+
+	if len(entries) == 0 {
+		b.WriteString(s.dimText.Render(s.l.T("No entries yet. Be the first!")) + "\n")
+		return b.String()
+	}
+
+	page := cursor / guestbookPageSize
+	pages := (len(entries) + guestbookPageSize - 1) / guestbookPageSize
+	end := min((page+1)*guestbookPageSize, len(entries))
+	for i := page * guestbookPageSize; i < end; i++ {
+		e := entries[i]
+		marker := "  "
+		if admin && i == cursor {
+			marker = s.accentText.Render(s.g.Bullet + " ")
+		}
+		meta := shortFingerprint(e.Fingerprint) + "  " + s.g.Dot + "  " + s.l.words(e.Time.Format("Jan 2, 2006"))
+		header := marker + s.accentText.Render(s.isolate(e.User)) + "  " + s.dimText.Render(meta)
+		if e.Hidden {
+			header += "  " + s.tag.Render(s.l.T("hidden"))
+		}
+		text := s.r.NewStyle().Width(contentWidth - 2).Foreground(s.theme.Text)
+		if e.Hidden {
+			text = text.Foreground(s.theme.Muted)
+		}
+		b.WriteString(header + "\n")
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, "  ", text.Render(e.Message)) + "\n")
+		if i < end-1 {
+			b.WriteString("\n")
+		}
+	}
+	if pages > 1 {
+		b.WriteString("\n" + s.dimText.Render(s.l.F("Page %d of %d", page+1, pages)) + "\n")
+	}
+	return b.String()
+}
+
+// renderSignForm is the box the sign form is drawn in.
+func (s styles) renderSignForm(input, signer, problem, hint string, width, height int) string {
+	title := s.accentText.Render(s.l.T("Sign the guestbook"))
+	rows := []string{title, s.dimText.Render(signer), "", input}
+	if problem != "" {
+		rows = append(rows, "", s.r.NewStyle().Foreground(s.theme.Pink).Render(problem))
+	}
+	rows = append(rows, "", hint)
+	return s.placeBox(lipgloss.JoinVertical(lipgloss.Left, rows...), width, height)
+}
+
+// signHint says how to sign, or why the visitor can't.
+func (m model) signHint() string {
+	if m.visitor.fingerprint == "" {
+		return m.locale.T("Connect with an SSH key to sign the guestbook.")
+	}
+	return m.locale.F("Press %s to sign the guestbook.", m.keys.Sign.Help().Key)
+}
+
+// plainGuestbook reads out the page of entries with the cursor on it.
+func plainGuestbook(l *locale, entries []guestbookEntry, cursor int, signHint string) string {
+	var b strings.Builder
+	b.WriteString(signHint + "\n\n")
+	if len(entries) == 0 {
+		b.WriteString(l.T("No entries yet. Be the first!") + "\n")
+		return b.String()
+	}
+	page := cursor / guestbookPageSize
+	pages := (len(entries) + guestbookPageSize - 1) / guestbookPageSize
+	b.WriteString(l.F("Page %d of %d", page+1, pages) + "\n\n")
+	end := min((page+1)*guestbookPageSize, len(entries))
+	for i := page * guestbookPageSize; i < end; i++ {
+		e := entries[i]
+		b.WriteString(l.F("Entry %d of %d: %s", i+1, len(entries), e.Message) + "\n")
+		b.WriteString(l.F("Signed by %s, key %s, on %s.", e.User, shortFingerprint(e.Fingerprint), l.words(e.Time.Format("January 2, 2006"))) + "\n\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSignGuestbookRefusedByKey(t *testing.T) {
+	st, err := openStore(filepath.Join(t.TempDir(), "p.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	limit := newThrottle(2, guestbookEvery)
+	start := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	sign := func(key string, after time.Duration) signedMsg {
+		e := guestbookEntry{User: "ada", Fingerprint: key, Message: "Hello", Time: start.Add(after)}
+		return signGuestbook(st, limit, "192.0.2.1", e)
+	}
+
+	if msg := sign("SHA256:a", 0); msg.err != nil || msg.wait != 0 {
+		t.Fatalf("first signing: wait %v, err %v", msg.wait, msg.err)
+	}
+	if msg := sign("SHA256:a", time.Minute); msg.wait == 0 {
+		t.Fatal("second signing with the same key went in")
+	}
+	if msg := sign("SHA256:b", 2*time.Minute); msg.err != nil || msg.wait != 0 {
+		t.Errorf("another key behind the address: wait %v, err %v; the refused signing counted", msg.wait, msg.err)
+	}
+	if msg := sign("SHA256:c", 3*time.Minute); msg.wait == 0 {
+		t.Error("third key went in past the address's limit")
+	}
+	if n := len(readGuestbook(st)); n != 2 {
+		t.Errorf("guestbook has %d entries, want 2", n)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testMessage = contactMessage{
+	Name:        "Ada Lovelace",
+	ReplyTo:     "ada@example.com",
+	Message:     "Hello there.",
+	Time:        time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC),
+	User:        "ada",
+	Fingerprint: "SHA256:abc",
+	IP:          "192.0.2.1",
+}
+
+// fakeSMTP is a stand-in relay that takes one message and sends the
+// commands it saw and the message's data on the returned channels.
+func fakeSMTP(t *testing.T) (addr string, cmds <-chan []string, data <-chan string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	cmdc, datac := make(chan []string, 1), make(chan string, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ESMTP")
+		var seen []string
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			verb, _, _ := strings.Cut(line, " ")
+			seen = append(seen, line)
+			switch strings.ToUpper(verb) {
+			case "EHLO", "HELO", "MAIL", "RCPT":
+				reply("250 OK")
+			case "DATA":
+				reply("354 Go ahead")
+				var b strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					b.WriteString(l)
+				}
+				datac <- b.String()
+				reply("250 Queued")
+			case "QUIT":
+				reply("221 Bye")
+				cmdc <- seen
+				return
+			default:
+				reply("502 Unknown command")
+			}
+		}
+	}()
+	return l.Addr().String(), cmdc, datac
+}
+
+func TestSMTPInbox(t *testing.T) {
+	addr, cmds, data := fakeSMTP(t)
+	in, err := parseInbox("smtp://" + addr + "?to=me@example.com&from=portfolio@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := in.deliver(testMessage); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+
+	got := <-data
+	for _, want := range []string{
+		"From: \"Ada Lovelace\" <portfolio@example.com>",
+		"To: <me@example.com>",
+		"Reply-To: \"Ada Lovelace\" <ada@example.com>",
+		"Hello there.",
+		"Sent over SSH by ada from 192.0.2.1, key SHA256:abc.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message lacks %q:\n%s", want, got)
+		}
+	}
+	seen := strings.Join(<-cmds, "\n")
+	for _, want := range []string{"MAIL FROM:<portfolio@example.com>", "RCPT TO:<me@example.com>"} {
+		if !strings.Contains(seen, want) {
+			t.Errorf("commands lack %q:\n%s", want, seen)
+		}
+	}
+}
+
+func TestSMTPInboxRefused(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+	u, _ := url.Parse("smtp://" + addr + "?to=me@example.com")
+	in, err := newSMTPInbox(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := in.deliver(testMessage); err == nil {
+		t.Error("deliver to a closed port succeeded")
+	}
+}
+
+func TestNewSMTPInbox(t *testing.T) {
+	tests := []struct {
+		url     string
+		addr    string
+		from    string
+		wantErr bool
+	}{
+		{url: "smtp://mail.example.com?to=me@example.com", addr: "mail.example.com:25", from: "me@example.com"},
+		{url: "smtp://u:p@mail.example.com:587?to=me@example.com&from=p@example.com", addr: "mail.example.com:587", from: "p@example.com"},
+		{url: "smtp://mail.example.com", wantErr: true},
+		{url: "smtp://mail.example.com?to=not-an-address", wantErr: true},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		in, err := newSMTPInbox(u)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("newSMTPInbox(%s) error = %v, want error %v", tt.url, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		s := in.(smtpInbox)
+		if s.addr != tt.addr || s.from != tt.from {
+			t.Errorf("newSMTPInbox(%s) = %s from %s, want %s from %s", tt.url, s.addr, s.from, tt.addr, tt.from)
+		}
+	}
+}
+
+func TestWebhookInbox(t *testing.T) {
+	var got contactMessage
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	in, err := parseInbox(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := in.deliver(testMessage); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if got != testMessage {
+		t.Errorf("webhook got %+v, want %+v", got, testMessage)
+	}
+}
+
+func TestWebhookInboxStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	in, _ := parseInbox(srv.URL)
+	if err := in.deliver(testMessage); err == nil {
+		t.Error("deliver succeeded despite a 503")
+	}
+}
+
+func TestMaildirInbox(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	in, err := parseInbox(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err := in.deliver(testMessage); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+	}
+
+	if tmp, _ := os.ReadDir(filepath.Join(dir, "tmp")); len(tmp) != 0 {
+		t.Errorf("tmp has %d files left in it", len(tmp))
+	}
+	files, err := os.ReadDir(filepath.Join(dir, "new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("new has %d files, want 2", len(files))
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "new", files[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	if strings.Contains(got, "\r\n") {
+		t.Error("message has CRLF line endings")
+	}
+	for _, want := range []string{"From: \"Ada Lovelace\" <ada@example.com>", "Hello there."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("message lacks %q:\n%s", want, got)
+		}
+	}
+}
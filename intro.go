@@ -0,0 +1,175 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/ssh"
+	"github.com/muesli/termenv"
+)
+
+// The intro plays once at the start of a session: a few boot-log lines, the
+// banner swept in from the left, then the bio typed out, before the tabs
+// appear. Any key or click skips it. It is left out where it would crawl or
+// look wrong: over slow links, without truecolor, in accessible mode and on
+// small terminals, and entirely with -intro=false.
+
+const (
+	introFPS          = 30 // frame-rate cap; frames are never ticked faster
+	bootLineFrames    = 4  // frames between boot-log lines
+	sweepFrames       = 20 // frames for the banner sweep
+	sweepBand         = 3  // columns of highlight at the sweep's leading edge
+	typeRunesPerFrame = 4  // bio characters typed per frame
+	introHoldFrames   = 20 // frames the finished intro stays up
+
+	// slowRTT is the round trip beyond which the link counts as slow: each
+	// frame would arrive late and the animation would stutter.
+	slowRTT = 150 * time.Millisecond
+)
+
+type introTickMsg struct{}
+
+func introTick() tea.Cmd {
+	return tea.Tick(time.Second/introFPS, func(time.Time) tea.Msg { return introTickMsg{} })
+}
+
+// intro is the intro's progress. It is over once playing is false.
+type intro struct {
+	playing bool
+	frame   int
+}
+
+// wantsIntro reports whether a session should see the intro. Returning
+// visitors have seen it already.
+func wantsIntro(a *app, v visitor, p termenv.Profile, tier layoutTier) bool {
+	return a.intro && !v.accessible && !v.slowLink && v.prefs.Visits <= 1 && p == termenv.TrueColor &&
+		(tier == tierNormal || tier == tierWide)
+}
+
+// slowLink reports whether a session's round trip is over slowRTT, timing a
+// request the client must answer, even if only to refuse it.
+func slowLink(s ssh.Session) bool {
+	reply := make(chan error, 1)
+	go func() {
+		_, err := s.SendRequest("keepalive@openssh.com", true, nil)
+		reply <- err
+	}()
+	select {
+	case err := <-reply:
+		return err != nil
+	case <-time.After(slowRTT):
+		return true
+	}
+}
+
+// bootLog is the intro's boot-log lines, counting p's content.
+func bootLog(l *locale, p portfolio) []string {
+	return []string{
+		l.T("Negotiating session"),
+		l.F("Loading %d roles", len(p.experiences)),
+		l.F("Loading %d projects", len(p.projects)),
+		l.F("Indexing %d skills", len(p.allSkills())),
+		l.T("Rendering banner"),
+	}
+}
+
+// introFrames is the length of the intro in frames.
+func (m model) introFrames() int {
+	return len(bootLog(nil, m.portfolio))*bootLineFrames + sweepFrames + len([]rune(plainMarkdown(m.portfolio.profile.Bio)))/typeRunesPerFrame + introHoldFrames
+}
+
+// updateIntro advances the intro, or skips it on a key press or click.
+func (m model) updateIntro(msg tea.Msg) (model, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case introTickMsg:
+		m.intro.frame++
+		if m.intro.frame >= m.introFrames() || m.tier == tierCompact || m.tier == tierTooSmall {
+			m.intro.playing = false
+			return m, nil, true
+		}
+		return m, introTick(), true
+	case tea.KeyMsg:
+		if msg.String() != "ctrl+c" {
+			m.intro.playing = false
+			return m, nil, true
+		}
+	case tea.MouseMsg:
+		if msg.Action == tea.MouseActionPress {
+			m.intro.playing = false
+			return m, nil, true
+		}
+	}
+	return m, nil, false
+}
+
+// renderIntro renders the intro as it stands at the current frame, filling
+// the screen above a hint on how to skip it.
+func (m model) renderIntro() string {
+	s := m.styles
+	hint := s.r.NewStyle().Width(m.width).Align(lipgloss.Center).Inherit(s.dimText).Render(s.l.T("Press any key to skip"))
+	return lipgloss.JoinVertical(lipgloss.Left,
+		s.r.NewStyle().Height(m.height-1).MaxHeight(m.height-1).Render(s.g.Text(m.introContent())),
+		hint,
+	)
+}
+
+// introContent is the intro's boot log, banner and bio, each appearing once
+// the one before it is complete.
+func (m model) introContent() string {
+	s := m.styles
+	frame := m.intro.frame
+	var b strings.Builder
+
+	boot := bootLog(s.l, m.portfolio)
+	for i, line := range boot {
+		if frame < i*bootLineFrames {
+			break
+		}
+		b.WriteString(s.dimText.Render("[ ") + s.greenText.Render("ok") + s.dimText.Render(" ] ") + s.mutedText.Render(line) + "\n")
+	}
+	frame -= len(boot) * bootLineFrames
+	if frame < 0 {
+		return s.contentBox.Render(b.String())
+	}
+	b.WriteString("\n")
+
+	name := m.portfolio.profile.Name
+	lines := banner(m.app.fonts, name, m.width-4)
+	if len(lines) == 0 {
+		lines = []string{name}
+	}
+	reveal := (linesWidth(lines) + sweepBand) * min(frame, sweepFrames) / sweepFrames
+	for i, line := range lines {
+		b.WriteString(s.sweepLine(line, bannerColor(s.theme.Banner, i, len(lines)), reveal) + "\n")
+	}
+	frame -= sweepFrames
+	if frame < 0 {
+		return s.contentBox.Render(b.String())
+	}
+	b.WriteString("\n")
+
+	// The bio is typed as plain text; its formatting appears with the tabs.
+	bio := []rune(plainMarkdown(m.portfolio.profile.Bio))
+	typed := string(bio[:min(frame*typeRunesPerFrame, len(bio))])
+	if len([]rune(typed)) < len(bio) {
+		typed += s.g.Block
+	}
+	b.WriteString(s.r.NewStyle().
+		Width(min(m.width-8, 70)).
+		Foreground(s.theme.Text).
+		Render(typed))
+
+	return s.contentBox.Render(b.String())
+}
+
+// sweepLine renders a banner line revealed up to column reveal, with the
+// columns just behind the edge highlighted.
+func (s styles) sweepLine(line string, color lipgloss.Color, reveal int) string {
+	r := []rune(line)
+	edge := min(reveal, len(r))
+	settled := max(min(reveal-sweepBand, len(r)), 0)
+	return s.r.NewStyle().Foreground(color).Bold(true).Render(string(r[:settled])) +
+		s.r.NewStyle().Foreground(s.theme.Text).Bold(true).Render(string(r[settled:edge]))
+}
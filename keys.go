@@ -0,0 +1,447 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+)
+
+// keyMap is the single table of key bindings. model.Update dispatches from
+// it, the viewport scrolls with it and the footer and help overlay are
+// rendered from it, so what the help says is always what the keys do.
+type keyMap struct {
+	Name         string
+	Next         key.Binding
+	Prev         key.Binding
+	Jump         key.Binding
+	Up           key.Binding
+	Down         key.Binding
+	PageUp       key.Binding
+	PageDown     key.Binding
+	HalfPageUp   key.Binding
+	HalfPageDown key.Binding
+	Top          key.Binding
+	Bottom       key.Binding
+	Timeline     key.Binding
+	Filter       key.Binding
+	NextItem     key.Binding
+	PrevItem     key.Binding
+	Theme        key.Binding
+	ThemeMenu    key.Binding
+	Select       key.Binding
+	Sign         key.Binding
+	Hide         key.Binding
+	Delete       key.Binding
+	Compose      key.Binding
+	Send         key.Binding
+	NextField    key.Binding
+	PrevField    key.Binding
+	Preview      key.Binding
+	Glyphs       key.Binding
+	Accessible   key.Binding
+	Keymap       key.Binding
+	Forget       key.Binding
+	Language     key.Binding
+	Help         key.Binding
+	Close        key.Binding
+	Quit         key.Binding
+}
+
+var _ help.KeyMap = keyMap{}
+
+// keymapPresets are the built-in key binding presets, in the order the
+// in-app switcher cycles through them.
+var keymapPresets = []string{"default", "vim", "emacs"}
+
+func validKeymap(name string) bool {
+	for _, p := range keymapPresets {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// nextKeymap returns the preset after name, wrapping around.
+func nextKeymap(name string) string {
+	for i, p := range keymapPresets {
+		if p == name {
+			return keymapPresets[(i+1)%len(keymapPresets)]
+		}
+	}
+	return keymapPresets[0]
+}
+
+// keyMapFor builds the named preset for a tab bar with tabs entries, falling
+// back to the default preset for unknown names.
+func keyMapFor(name string, tabs int) keyMap {
+	var k keyMap
+	switch name {
+	case "vim":
+		k = vimKeyMap()
+	case "emacs":
+		k = emacsKeyMap()
+	default:
+		k = defaultKeyMap()
+	}
+
+	jump := make([]string, 0, tabs)
+	for i := 1; i <= min(tabs, 9); i++ {
+		jump = append(jump, strconv.Itoa(i))
+	}
+	k.Jump = key.NewBinding(
+		key.WithKeys(jump...),
+		key.WithHelp(fmt.Sprintf("1-%d", len(jump)), "jump to tab"),
+	)
+	k.Keymap.SetHelp(k.Keymap.Help().Key, "keys: "+k.Name)
+	return k
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Name: "default",
+		Next: key.NewBinding(
+			key.WithKeys("tab", "right", "l"),
+			key.WithHelp("→/l", "next tab"),
+		),
+		Prev: key.NewBinding(
+			key.WithKeys("shift+tab", "left", "h"),
+			key.WithHelp("←/h", "prev tab"),
+		),
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "scroll up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "scroll down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "b"),
+			key.WithHelp("b/pgup", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", " ", "f"),
+			key.WithHelp("f/pgdn", "page down"),
+		),
+		HalfPageUp: key.NewBinding(
+			key.WithKeys("u", "ctrl+u"),
+			key.WithHelp("u", "½ page up"),
+		),
+		HalfPageDown: key.NewBinding(
+			key.WithKeys("d", "ctrl+d"),
+			key.WithHelp("d", "½ page down"),
+		),
+		Top: key.NewBinding(
+			key.WithKeys("home"),
+			key.WithHelp("home", "top"),
+		),
+		Bottom: key.NewBinding(
+			key.WithKeys("end"),
+			key.WithHelp("end", "bottom"),
+		),
+		Timeline: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "timeline"),
+		),
+		Filter: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "filter by tag"),
+		),
+		NextItem: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", "next skill"),
+		),
+		PrevItem: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "prev skill"),
+		),
+		Theme: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "next theme"),
+		),
+		ThemeMenu: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "themes"),
+		),
+		Select: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+		Sign: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sign"),
+		),
+		Hide: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "hide/show"),
+		),
+		Delete: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "delete"),
+		),
+		Compose: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "message me"),
+		),
+		Send: key.NewBinding(
+			key.WithKeys("ctrl+s"),
+			key.WithHelp("ctrl+s", "send"),
+		),
+		NextField: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "next field"),
+		),
+		PrevField: key.NewBinding(
+			key.WithKeys("shift+tab"),
+			key.WithHelp("shift+tab", "prev field"),
+		),
+		Preview: key.NewBinding(
+			key.WithKeys("ctrl+r"),
+			key.WithHelp("ctrl+r", "preview"),
+		),
+		Glyphs: key.NewBinding(
+			key.WithKeys("A"),
+			key.WithHelp("A", "ascii mode"),
+		),
+		Accessible: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "screen reader"),
+		),
+		Keymap: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "keys"),
+		),
+		Language: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "language"),
+		),
+		Forget: key.NewBinding(
+			key.WithKeys("F"),
+			key.WithHelp("F", "forget me"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "help"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "close"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "ctrl+c"),
+			key.WithHelp("q", "quit"),
+		),
+	}
+}
+
+// vimKeyMap keeps hjkl for movement and adds vim's paging and jump keys.
+func vimKeyMap() keyMap {
+	k := defaultKeyMap()
+	k.Name = "vim"
+	k.Next = key.NewBinding(
+		key.WithKeys("l", "L", "tab"),
+		key.WithHelp("l/L", "next tab"),
+	)
+	k.Prev = key.NewBinding(
+		key.WithKeys("h", "H", "shift+tab"),
+		key.WithHelp("h/H", "prev tab"),
+	)
+	k.Up.SetHelp("k", "scroll up")
+	k.Down.SetHelp("j", "scroll down")
+	k.PageUp = key.NewBinding(
+		key.WithKeys("ctrl+b"),
+		key.WithHelp("ctrl+b", "page up"),
+	)
+	k.PageDown = key.NewBinding(
+		key.WithKeys("ctrl+f"),
+		key.WithHelp("ctrl+f", "page down"),
+	)
+	k.HalfPageUp = key.NewBinding(
+		key.WithKeys("ctrl+u"),
+		key.WithHelp("ctrl+u", "½ page up"),
+	)
+	k.HalfPageDown = key.NewBinding(
+		key.WithKeys("ctrl+d"),
+		key.WithHelp("ctrl+d", "½ page down"),
+	)
+	k.Top = key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "top"),
+	)
+	k.Bottom = key.NewBinding(
+		key.WithKeys("G"),
+		key.WithHelp("G", "bottom"),
+	)
+	return k
+}
+
+// emacsKeyMap uses emacs motion keys, with ctrl+g to back out of overlays.
+func emacsKeyMap() keyMap {
+	k := defaultKeyMap()
+	k.Name = "emacs"
+	k.Next = key.NewBinding(
+		key.WithKeys("ctrl+f", "right", "tab"),
+		key.WithHelp("C-f", "next tab"),
+	)
+	k.Prev = key.NewBinding(
+		key.WithKeys("ctrl+b", "left", "shift+tab"),
+		key.WithHelp("C-b", "prev tab"),
+	)
+	k.Up = key.NewBinding(
+		key.WithKeys("ctrl+p", "up"),
+		key.WithHelp("C-p", "scroll up"),
+	)
+	k.Down = key.NewBinding(
+		key.WithKeys("ctrl+n", "down"),
+		key.WithHelp("C-n", "scroll down"),
+	)
+	k.PageUp = key.NewBinding(
+		key.WithKeys("alt+v", "pgup"),
+		key.WithHelp("M-v", "page up"),
+	)
+	k.PageDown = key.NewBinding(
+		key.WithKeys("ctrl+v", "pgdown"),
+		key.WithHelp("C-v", "page down"),
+	)
+	k.HalfPageUp = key.NewBinding()
+	k.HalfPageDown = key.NewBinding()
+	k.Top = key.NewBinding(
+		key.WithKeys("alt+<", "home"),
+		key.WithHelp("M-<", "top"),
+	)
+	k.Bottom = key.NewBinding(
+		key.WithKeys("alt+>", "end"),
+		key.WithHelp("M->", "bottom"),
+	)
+	k.Close = key.NewBinding(
+		key.WithKeys("ctrl+g", "esc"),
+		key.WithHelp("C-g", "close"),
+	)
+	k.Quit = key.NewBinding(
+		key.WithKeys("ctrl+c", "q"),
+		key.WithHelp("C-c/q", "quit"),
+	)
+	return k
+}
+
+// ShortHelp is the footer: tab navigation, whatever the current tab adds,
+// and how to get the full list.
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Next, k.Prev, k.Timeline, k.NextItem, k.Select, k.Sign, k.Compose, k.Close, k.Help, k.Quit}
+}
+
+// FullHelp is the help overlay, one column per group.
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.Next, k.Prev, k.Jump, k.Timeline, k.NextItem, k.PrevItem, k.Select, k.Filter, k.Sign, k.Hide, k.Delete, k.Compose},
+		{k.Up, k.Down, k.PageUp, k.PageDown, k.HalfPageUp, k.HalfPageDown, k.Top, k.Bottom},
+		{k.Theme, k.ThemeMenu, k.Glyphs, k.Accessible, k.Keymap, k.Language, k.Forget, k.Help, k.Close, k.Quit},
+	}
+}
+
+// mapHelp returns k with f applied to every binding's help text.
+func (k keyMap) mapHelp(f func(string) string) keyMap {
+	for _, b := range []*key.Binding{
+		&k.Next, &k.Prev, &k.Jump, &k.Up, &k.Down, &k.PageUp, &k.PageDown,
+		&k.HalfPageUp, &k.HalfPageDown, &k.Top, &k.Bottom, &k.Timeline,
+		&k.Filter, &k.NextItem, &k.PrevItem, &k.Theme, &k.ThemeMenu, &k.Select,
+		&k.Sign, &k.Hide, &k.Delete, &k.Compose, &k.Send, &k.NextField,
+		&k.PrevField, &k.Preview, &k.Glyphs, &k.Accessible, &k.Keymap,
+		&k.Language, &k.Forget, &k.Help, &k.Close, &k.Quit,
+	} {
+		h := b.Help()
+		b.SetHelp(f(h.Key), f(h.Desc))
+	}
+	return k
+}
+
+// viewportKeyMap hands the scrolling bindings to the viewport. Horizontal
+// scrolling is left unbound because left/right switch tabs.
+func (k keyMap) viewportKeyMap() viewport.KeyMap {
+	return viewport.KeyMap{
+		PageDown:     k.PageDown,
+		PageUp:       k.PageUp,
+		HalfPageUp:   k.HalfPageUp,
+		HalfPageDown: k.HalfPageDown,
+		Up:           k.Up,
+		Down:         k.Down,
+	}
+}
+
+// forTab enables the bindings that only apply on the given tab, and those
+// that only apply while an overlay is open. Accessible mode has no scrolling,
+// overlays or visual settings, so it turns those bindings off. roles is set
+// when Experience shows a list of roles to select from, reading while a post
+// is open on Writing, signer when the visitor has a key to sign the
+// guestbook with, remembered when their preferences are saved, admin when
+// they may moderate the guestbook and edit content and inbox when there is
+// an inbox for the contact form.
+func (k keyMap) forTab(tab Tab, o overlay, accessible, roles, reading, signer, remembered, admin, inbox bool) keyMap {
+	experience := tab.Type == sectionTimeline && tab.builtin()
+	writing := tab.Type == sectionPosts
+	guestbook := tab.Type == sectionGuestbook
+	contact := tab.Type == sectionList && tab.builtin()
+	editing := tab.Type == sectionAdmin && !accessible
+	k.Timeline.SetEnabled(experience && !accessible)
+	items := tab.Type == sectionTags && tab.builtin() || experience && roles || writing || guestbook || editing
+	k.NextItem.SetEnabled(items)
+	k.PrevItem.SetEnabled(items)
+	item := "skill"
+	switch {
+	case experience:
+		item = "role"
+	case writing:
+		item = "post"
+	case guestbook && admin && !accessible, editing:
+		item = "entry"
+	case guestbook:
+		item = "page"
+	}
+	k.NextItem.SetHelp(k.NextItem.Help().Key, "next "+item)
+	k.PrevItem.SetHelp(k.PrevItem.Help().Key, "prev "+item)
+	list := writing && !reading && o == noOverlay
+	k.Filter.SetEnabled(list && !accessible)
+	k.Sign.SetEnabled(guestbook && signer && o != signOverlay)
+	k.Hide.SetEnabled(guestbook && admin && o != signOverlay && !accessible)
+	k.Delete.SetEnabled(guestbook && admin && o != signOverlay && !accessible)
+	k.Compose.SetEnabled(contact && inbox && o != contactOverlay)
+	form := o == contactOverlay || o == editOverlay
+	k.Send.SetEnabled(form)
+	k.NextField.SetEnabled(form)
+	k.PrevField.SetEnabled(form)
+	k.Preview.SetEnabled(o == editOverlay)
+	k.Close.SetEnabled(o != noOverlay || reading)
+	k.Select.SetEnabled(o == themeOverlay || o == signOverlay || form || list && !accessible || editing && o == noOverlay)
+	switch {
+	case editing && o == noOverlay:
+		k.Select.SetHelp(k.Select.Help().Key, "edit")
+	case o == noOverlay:
+		k.Close.SetHelp(k.Close.Help().Key, "back")
+		k.Select.SetHelp(k.Select.Help().Key, "read")
+	default:
+		k.Close.SetHelp(k.Close.Help().Key, "close")
+		k.Select.SetHelp(k.Select.Help().Key, "select")
+	}
+	for _, b := range []*key.Binding{
+		&k.Up, &k.Down, &k.PageUp, &k.PageDown, &k.HalfPageUp, &k.HalfPageDown,
+		&k.Top, &k.Bottom, &k.Theme, &k.ThemeMenu, &k.Glyphs,
+	} {
+		b.SetEnabled(!accessible)
+	}
+	k.Forget.SetEnabled(signer)
+	if remembered {
+		k.Forget.SetHelp(k.Forget.Help().Key, "forget me")
+	} else {
+		k.Forget.SetHelp(k.Forget.Help().Key, "remember me")
+	}
+	if accessible {
+		k.Accessible.SetHelp(k.Accessible.Help().Key, "full layout")
+	} else {
+		k.Accessible.SetHelp(k.Accessible.Help().Key, "screen reader")
+	}
+	return k
+}
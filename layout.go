@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// zoneKind is what a clickable region of the content is.
+type zoneKind int
+
+const (
+	zoneTag     zoneKind = iota // a skill tag; index is into allSkills
+	zoneCard                    // a project card; index is into projects
+	zoneContact                 // a contact link; index is into contacts
+	zoneRole                    // a role in the wide Experience list; index is into sortedExperiences
+	zonePost                    // a post in the Writing list; index is into the filtered posts
+	zonePostTag                 // a tag filter on Writing; index is into "" and then postTags
+	zoneEdit                    // an edit target on Edit; index is into the editor's targets
+)
+
+// rect is a rectangle of terminal cells.
+type rect struct {
+	x, y, w, h int
+}
+
+func (r rect) contains(x, y int) bool {
+	return x >= r.x && x < r.x+r.w && y >= r.y && y < r.y+r.h
+}
+
+// zone is a clickable region of a tab's content, in content coordinates:
+// columns from the content's left edge and lines from its first line.
+type zone struct {
+	kind  zoneKind
+	index int
+	rect  rect
+}
+
+// Renderers mark clickable elements with zero-width escape sequences, which
+// lipgloss measures, wraps and joins like any other escape. scanZones then
+// finds them in the final rendered content, so the recorded rectangles are
+// wherever the element actually ended up, measured in display cells.
+var zoneMarkerRe = regexp.MustCompile(`\x1b\[(\d+);(\d+);([01])z`)
+
+// markZone wraps rendered text in start and end markers for a zone.
+func markZone(kind zoneKind, index int, rendered string) string {
+	return fmt.Sprintf("\x1b[%d;%d;0z%s\x1b[%d;%d;1z", kind, index, rendered, kind, index)
+}
+
+// scanZones strips the zone markers from content and returns it along with
+// the zones they delimited. A zone spanning several lines is taken to be a
+// box: its end marker trails the bottom line, so it spans from the start
+// column to the end column.
+func scanZones(content string) (string, []zone) {
+	if !strings.Contains(content, "\x1b[") {
+		return content, nil
+	}
+
+	type point struct{ x, y int }
+	starts := map[[2]int]point{}
+	var zones []zone
+	lines := strings.Split(content, "\n")
+	for y, line := range lines {
+		matches := zoneMarkerRe.FindAllStringSubmatchIndex(line, -1)
+		for _, m := range matches {
+			kind, _ := strconv.Atoi(line[m[2]:m[3]])
+			index, _ := strconv.Atoi(line[m[4]:m[5]])
+			x := lipgloss.Width(zoneMarkerRe.ReplaceAllString(line[:m[0]], ""))
+			id := [2]int{kind, index}
+			if line[m[6]:m[7]] == "0" {
+				starts[id] = point{x, y}
+				continue
+			}
+			start, ok := starts[id]
+			if !ok {
+				continue
+			}
+			delete(starts, id)
+			left, right := min(start.x, x), max(start.x, x)
+			zones = append(zones, zone{
+				kind:  zoneKind(kind),
+				index: index,
+				rect:  rect{x: left, y: start.y, w: right - left, h: y - start.y + 1},
+			})
+		}
+		if matches != nil {
+			lines[y] = zoneMarkerRe.ReplaceAllString(line, "")
+		}
+	}
+	return strings.Join(lines, "\n"), zones
+}
+
+// zoneAt is the innermost zone containing the point, if any. Zones close
+// in the order they nest, so the first match is the innermost.
+func zoneAt(zones []zone, x, y int) (zone, bool) {
+	for _, z := range zones {
+		if z.rect.contains(x, y) {
+			return z, true
+		}
+	}
+	return zone{}, false
+}
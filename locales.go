@@ -0,0 +1,131 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// The interface is written in English and translated at display time. Each
+// other language is a message catalog in locales/, named by its language
+// code, that maps English strings, format strings included, to their
+// translation; anything it leaves out is shown in English.
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// locale is a language the interface can be shown in.
+type locale struct {
+	Tag      string            `json:"-"`    // language code, e.g. "de"
+	Name     string            `json:"name"` // the language's name for itself
+	RTL      bool              `json:"rtl"`  // written right to left
+	Messages map[string]string `json:"messages"`
+}
+
+var english = &locale{Tag: "en", Name: "English"}
+
+// T translates msg, or returns it as is if l has no translation.
+func (l *locale) T(msg string) string {
+	if l == nil {
+		return msg
+	}
+	if t, ok := l.Messages[msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// F formats args with the translation of format.
+func (l *locale) F(format string, args ...any) string {
+	return fmt.Sprintf(l.T(format), args...)
+}
+
+// words translates text a word at a time, for generated text such as dates
+// ("Jan 2019 — Present") and tenures ("3 yrs 4 mos").
+func (l *locale) words(text string) string {
+	if l == nil || len(l.Messages) == 0 {
+		return text
+	}
+	w := strings.Split(text, " ")
+	for i := range w {
+		w[i] = l.T(w[i])
+	}
+	return strings.Join(w, " ")
+}
+
+// rtl reports whether l is written right to left, which mirrors the layout.
+func (l *locale) rtl() bool {
+	return l != nil && l.RTL
+}
+
+// bundledLocales is English followed by the embedded catalogs in order of
+// language code. A broken catalog is a build mistake, so it panics.
+func bundledLocales() []*locale {
+	names, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	locales := []*locale{english}
+	for _, e := range names {
+		data, err := localeFiles.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		l := &locale{Tag: strings.TrimSuffix(e.Name(), ".json")}
+		if err := json.Unmarshal(data, l); err != nil {
+			panic(fmt.Sprintf("locale %s: %v", l.Tag, err))
+		}
+		locales = append(locales, l)
+	}
+	sort.SliceStable(locales[1:], func(i, j int) bool { return locales[1+i].Tag < locales[1+j].Tag })
+	return locales
+}
+
+// findLocale looks up a locale by language code.
+func findLocale(locales []*locale, tag string) (*locale, bool) {
+	for _, l := range locales {
+		if l.Tag == tag {
+			return l, true
+		}
+	}
+	return nil, false
+}
+
+// localeTag is the language code of a client's locale, from the first of
+// LC_ALL, LC_MESSAGES and LANG that is set: "de" for "de_DE.UTF-8". It is ""
+// for the C and POSIX locales or when none is set.
+func localeTag(environ []string) string {
+	env := map[string]string{}
+	for _, kv := range environ {
+		if k, v, ok := strings.Cut(kv, "="); ok {
+			env[k] = v
+		}
+	}
+	for _, k := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := env[k]
+		if v == "" {
+			continue
+		}
+		tag, _, _ := strings.Cut(v, ".")
+		tag, _, _ = strings.Cut(tag, "_")
+		tag, _, _ = strings.Cut(tag, "@")
+		if tag == "C" || tag == "POSIX" {
+			return ""
+		}
+		return strings.ToLower(tag)
+	}
+	return ""
+}
+
+// nextLocale returns the locale after l, wrapping around.
+func nextLocale(locales []*locale, l *locale) *locale {
+	for i, o := range locales {
+		if o == l {
+			return locales[(i+1)%len(locales)]
+		}
+	}
+	return locales[0]
+}
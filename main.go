@@ -5,30 +5,152 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
 	"github.com/charmbracelet/wish/activeterm"
 	"github.com/charmbracelet/wish/bubbletea"
 	"github.com/charmbracelet/wish/logging"
+	"github.com/muesli/termenv"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 const host = "0.0.0.0"
 
+// app holds the configuration and state shared by every session.
+type app struct {
+	store     *store
+	keymap    string
+	themes    []Theme
+	theme     string           // theme name, or "auto" to match the terminal background
+	profile   *termenv.Profile // forced color profile, or nil to detect it
+	fonts     []*figFont       // banner fonts, largest first
+	intro     bool             // play the intro where the session suits it
+	posts     *postLibrary     // the Writing tab's posts, or nil for none
+	content   *contentFile     // the tab bar, less Writing, Guestbook and Edit
+	locales   []*locale        // interface languages, English first
+	guestbook bool             // show the Guestbook tab
+	admins    map[string]bool  // fingerprints of the admin keys
+	inbox     inbox            // where contact form messages go, or nil for no form
+	throttle  *throttle        // contact form messages per IP address
+	signings  *throttle        // guestbook signings per IP address
+}
+
+// colorProfiles are the values accepted by -force-profile.
+var colorProfiles = map[string]termenv.Profile{
+	"truecolor": termenv.TrueColor,
+	"256":       termenv.ANSI256,
+	"16":        termenv.ANSI,
+	"none":      termenv.Ascii,
+}
+
+// themeFor returns the configured theme for a session's renderer.
+func (a *app) themeFor(r *lipgloss.Renderer) Theme {
+	if t, ok := findTheme(a.themes, a.theme); ok {
+		return t
+	}
+	return autoTheme(r)
+}
+
 func main() {
+	var err error
 	port := flag.Int("p", 22, "port to listen on")
+	keymap := flag.String("keymap", "default", "default key bindings: default, vim or emacs")
+	dbPath := flag.String("db", "data/portfolio.db", "path to the visitor preferences database")
+	theme := flag.String("theme", "auto", "color theme, or auto to pick dark or light from the terminal background")
+	themesPath := flag.String("themes", "", "JSON file of custom themes")
+	tabsPath := flag.String("tabs", "data/tabs.json", "JSON file of the tabs' content, made on the first save from the Edit tab; empty for the built-in tabs alone")
+	forceProfile := flag.String("force-profile", "", "color profile to use instead of detecting it: truecolor, 256, 16 or none")
+	postsDir := flag.String("posts", "posts", "directory of Markdown posts for the Writing tab; empty for none")
+	intro := flag.Bool("intro", true, "play an intro animation at the start of each session")
+	guestbook := flag.Bool("guestbook", true, "show the Guestbook tab")
+	adminKeys := flag.String("admin-keys", "", "authorized_keys file of the admins' public keys")
+	retention := flag.Int("retention", 90, "days to remember visitors who don't come back; 0 to remember them for good")
+	inboxSpec := flag.String("inbox", "", "where contact form messages go: a Maildir directory, smtp://host:port?to=address or a webhook URL; empty for no form")
+	font := flag.String("font", defaultFont, "banner font: standard, small, mini or the path to a FIGlet .flf file")
 	flag.Parse()
 
+	var profile *termenv.Profile
+	if *forceProfile != "" {
+		p, ok := colorProfiles[*forceProfile]
+		if !ok {
+			log.Fatalf("Unknown color profile %q", *forceProfile)
+		}
+		profile = &p
+	}
+
+	if !validKeymap(*keymap) {
+		log.Fatalf("Unknown keymap %q", *keymap)
+	}
+
+	themes := builtinThemes
+	if *themesPath != "" {
+		if themes, err = loadThemes(*themesPath); err != nil {
+			log.Fatalf("Could not load themes: %v", err)
+		}
+	}
+	if _, ok := findTheme(themes, *theme); !ok && *theme != "auto" {
+		log.Fatalf("Unknown theme %q", *theme)
+	}
+
+	locales := bundledLocales()
+	content, err := openContent(*tabsPath, locales)
+	if err != nil {
+		log.Fatalf("Could not load tabs: %v", err)
+	}
+	go content.watch()
+
+	var admins map[string]bool
+	if *adminKeys != "" {
+		if admins, err = loadAdminKeys(*adminKeys); err != nil {
+			log.Fatalf("Could not load admin keys: %v", err)
+		}
+	}
+
+	var in inbox
+	if *inboxSpec != "" {
+		if in, err = parseInbox(*inboxSpec); err != nil {
+			log.Fatalf("Could not open inbox: %v", err)
+		}
+	}
+
+	fonts, err := bannerFonts(*font)
+	if err != nil {
+		log.Fatalf("Could not load font: %v", err)
+	}
+
+	st, err := openStore(*dbPath)
+	if err != nil {
+		log.Fatalf("Could not open database: %v", err)
+	}
+	defer st.Close()
+	go pruneStore(st, time.Duration(*retention)*24*time.Hour)
+
+	a := &app{store: st, keymap: *keymap, themes: themes, theme: *theme, profile: profile, fonts: fonts, intro: *intro, content: content, locales: locales, guestbook: *guestbook, admins: admins, inbox: in, throttle: newThrottle(contactLimit, contactWindow), signings: newThrottle(guestbookPerIP, guestbookEvery)}
+	if *postsDir != "" {
+		a.posts = newPostLibrary(*postsDir)
+	}
+
 	s, err := wish.NewServer(
 		wish.WithAddress(fmt.Sprintf("%s:%d", host, *port)),
 		wish.WithHostKeyPath(".ssh/id_ed25519"),
+		// Any key is accepted; it only identifies returning visitors. Clients
+		// without a key fall through to keyboard-interactive, also accepted.
+		wish.WithPublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool { return true }),
+		wish.WithKeyboardInteractiveAuth(func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool { return true }),
 		wish.WithMiddleware(
-			bubbletea.Middleware(teaHandler),
+			bubbletea.Middleware(a.teaHandler),
+			resetBidiMode,
+			a.saveLastTab,
 			activeterm.Middleware(),
 			logging.Middleware(),
 		),
@@ -58,7 +180,7 @@ func main() {
 	}
 }
 
-func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
+func (a *app) teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
 	pty, _, _ := s.Pty()
 	w := pty.Window.Width
 	h := pty.Window.Height
@@ -69,6 +191,86 @@ func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
 		h = 24
 	}
 	renderer := bubbletea.MakeRenderer(s)
-	m := newModel(w, h, renderer)
+	switch {
+	case a.profile != nil:
+		renderer.SetColorProfile(*a.profile)
+	case noColor(s.Environ()):
+		renderer.SetColorProfile(termenv.Ascii)
+	}
+	v := a.visitor(s)
+	m := newModel(w, h, renderer, a, v)
+	if v.accessible {
+		return m, nil
+	}
 	return m, []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseAllMotion()}
 }
+
+// visitor identifies the session's visitor by public key fingerprint and
+// loads their saved preferences. Visitors without a key are anonymous.
+func (a *app) visitor(s ssh.Session) visitor {
+	pty, _, _ := s.Pty()
+	v := visitor{
+		user:       s.User(),
+		ip:         remoteIP(s),
+		store:      a.store,
+		glyphs:     detectGlyphs(pty.Term, s.Environ()),
+		accessible: wantsAccessible(s.User(), s.Environ()),
+		bidi:       &atomic.Bool{},
+	}
+	s.Context().SetValue(bidiKey{}, v.bidi)
+	if a.intro && !v.accessible {
+		v.slowLink = slowLink(s)
+	}
+	if pk := s.PublicKey(); pk != nil {
+		v.fingerprint = gossh.FingerprintSHA256(pk)
+		v.admin = a.admins[v.fingerprint]
+		p, optedOut, err := a.store.visit(v.fingerprint, time.Now().UTC())
+		if err != nil {
+			log.Printf("Could not load preferences: %v", err)
+		}
+		v.prefs = p
+		v.remember = !optedOut
+		v.last = &lastTab{fingerprint: v.fingerprint}
+		s.Context().SetValue(lastTabKey{}, v.last)
+	}
+	// A user name naming a language beats a saved choice, which beats the
+	// client's locale.
+	v.locale = localeTag(s.Environ())
+	if v.prefs.Locale != "" {
+		v.locale = v.prefs.Locale
+	}
+	if _, ok := findLocale(a.locales, s.User()); ok {
+		v.locale = s.User()
+	}
+	return v
+}
+
+// remoteIP is the address a session connected from, without the port.
+func remoteIP(s ssh.Session) string {
+	addr := s.RemoteAddr().String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+// tabsIn is the tab bar for locale l, less Writing and Edit: the tabs of its
+// own content file, if it has one, and the Guestbook.
+func (a *app) tabsIn(l *locale) []Tab {
+	tabs := a.content.tabsIn(l.Tag)
+	if a.guestbook {
+		tabs = append(append([]Tab(nil), tabs...), Tab{Title: guestbookTab, Type: sectionGuestbook})
+	}
+	return tabs
+}
+
+// noColor reports whether the client asked for no color by sending NO_COLOR
+// (https://no-color.org) in its environment.
+func noColor(environ []string) bool {
+	for _, kv := range environ {
+		if v, ok := strings.CutPrefix(kv, "NO_COLOR="); ok && v != "" {
+			return true
+		}
+	}
+	return false
+}
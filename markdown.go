@@ -0,0 +1,346 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The bio and the descriptions in content.go are Markdown, so they can be
+// formatted without touching the renderers. Only a small subset is read:
+// paragraphs, headings, bulleted and numbered lists, fenced code blocks, and
+// inline emphasis, strong emphasis, code and links. Anything else is shown as
+// written.
+
+type mdBlockKind int
+
+const (
+	mdParagraph mdBlockKind = iota
+	mdHeading
+	mdList
+	mdCode
+)
+
+// mdBlock is one block of a Markdown document. Paragraphs and headings have
+// a single line of text, with their source lines joined; lists have one per
+// item; code blocks keep their lines as written.
+type mdBlock struct {
+	kind    mdBlockKind
+	ordered bool
+	lines   []string
+}
+
+var (
+	mdBulletRe  = regexp.MustCompile(`^\s*[-*+]\s+`)
+	mdNumberRe  = regexp.MustCompile(`^\s*\d+[.)]\s+`)
+	mdHeadingRe = regexp.MustCompile(`^#{1,6}\s+`)
+)
+
+// parseMarkdown splits a document into blocks.
+func parseMarkdown(md string) []mdBlock {
+	var blocks []mdBlock
+	var cur *mdBlock
+	flush := func() {
+		if cur != nil {
+			blocks = append(blocks, *cur)
+			cur = nil
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			code := mdBlock{kind: mdCode}
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				code.lines = append(code.lines, lines[i])
+			}
+			blocks = append(blocks, code)
+
+		case trimmed == "":
+			flush()
+
+		case mdHeadingRe.MatchString(trimmed):
+			flush()
+			blocks = append(blocks, mdBlock{kind: mdHeading, lines: []string{mdHeadingRe.ReplaceAllString(trimmed, "")}})
+
+		case mdBulletRe.MatchString(line), mdNumberRe.MatchString(line):
+			ordered := !mdBulletRe.MatchString(line)
+			if cur == nil || cur.kind != mdList || cur.ordered != ordered {
+				flush()
+				cur = &mdBlock{kind: mdList, ordered: ordered}
+			}
+			item := mdBulletRe.ReplaceAllString(line, "")
+			if ordered {
+				item = mdNumberRe.ReplaceAllString(line, "")
+			}
+			cur.lines = append(cur.lines, strings.TrimSpace(item))
+
+		case cur != nil:
+			// A continuation line joins the paragraph or the list item above.
+			last := &cur.lines[len(cur.lines)-1]
+			*last += " " + trimmed
+
+		default:
+			cur = &mdBlock{kind: mdParagraph, lines: []string{trimmed}}
+		}
+	}
+	flush()
+	return blocks
+}
+
+// mdSpan is a run of inline text with one set of formatting.
+type mdSpan struct {
+	text       string
+	strong, em bool
+	code       bool
+	url        string // set for link text
+}
+
+// parseInline splits a line of text into spans. Unclosed markers are kept as
+// literal text.
+func parseInline(text string) []mdSpan {
+	return appendInline(nil, text, mdSpan{})
+}
+
+func appendInline(spans []mdSpan, text string, format mdSpan) []mdSpan {
+	var plain strings.Builder
+	emit := func() {
+		if plain.Len() > 0 {
+			sp := format
+			sp.text = plain.String()
+			spans = append(spans, sp)
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '\\' && len(rest) > 1 && strings.ContainsRune("\\`*_[]()#+-.!", rune(rest[1])):
+			plain.WriteByte(rest[1])
+			i += 2
+			continue
+
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end >= 0 {
+				emit()
+				sp := format
+				sp.text, sp.code = rest[1:1+end], true
+				spans = append(spans, sp)
+				i += end + 2
+				continue
+			}
+
+		case strings.HasPrefix(rest, "**"), strings.HasPrefix(rest, "__"):
+			if end := closingDelim(rest[2:], rest[:2]); end > 0 {
+				emit()
+				inner := format
+				inner.strong = true
+				spans = appendInline(spans, rest[2:2+end], inner)
+				i += end + 4
+				continue
+			}
+
+		case rest[0] == '*' || rest[0] == '_':
+			// An underscore inside a word, as in snake_case, is not emphasis.
+			wordStart := i == 0 || !isWordByte(text[i-1])
+			if end := closingDelim(rest[1:], rest[:1]); end > 0 && (rest[0] == '*' || wordStart) {
+				emit()
+				inner := format
+				inner.em = true
+				spans = appendInline(spans, rest[1:1+end], inner)
+				i += end + 2
+				continue
+			}
+
+		case rest[0] == '[':
+			if mid := strings.Index(rest, "]("); mid > 0 {
+				if end := strings.IndexByte(rest[mid:], ')'); end > 0 {
+					emit()
+					inner := format
+					inner.url = rest[mid+2 : mid+end]
+					spans = appendInline(spans, rest[1:mid], inner)
+					i += mid + end + 1
+					continue
+				}
+			}
+		}
+		plain.WriteByte(rest[0])
+		i++
+	}
+	emit()
+	return spans
+}
+
+// closingDelim is the position in s of the delimiter that closes emphasis
+// opened just before s, or -1. As in CommonMark, the opening delimiter can't
+// be followed by a space nor the closing one preceded by one, so the
+// asterisks in "5 * 3 * 2" stay as they are. A single delimiter isn't closed
+// by a double one, which opens or closes strong emphasis inside it.
+func closingDelim(s, delim string) int {
+	if s == "" || isSpaceByte(s[0]) {
+		return -1
+	}
+	for i := 1; i < len(s); i++ {
+		if len(delim) == 1 && strings.HasPrefix(s[i:], delim+delim) {
+			i++ // strong emphasis nested inside
+			continue
+		}
+		if strings.HasPrefix(s[i:], delim) && !isSpaceByte(s[i-1]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isSpaceByte(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n'
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// markdown renders md in width cells, with base as the style of unformatted
+// text.
+func (s styles) markdown(md string, width int, base lipgloss.Style) string {
+	var out []string
+	for _, block := range parseMarkdown(md) {
+		switch block.kind {
+		case mdParagraph:
+			out = append(out, s.mdWrap(parseInline(block.lines[0]), width, base))
+
+		case mdHeading:
+			out = append(out, s.mdWrap(parseInline(block.lines[0]), width, base.Foreground(s.theme.Accent).Bold(true)))
+
+		case mdList:
+			var items []string
+			for i, item := range block.lines {
+				marker := s.bullet.Render(s.g.Bullet + " ")
+				if block.ordered {
+					marker = s.bullet.Render(strconv.Itoa(i+1) + ". ")
+				}
+				text := s.mdWrap(parseInline(item), width-lipgloss.Width(marker), base)
+				items = append(items, lipgloss.JoinHorizontal(lipgloss.Top, marker, text))
+			}
+			out = append(out, strings.Join(items, "\n"))
+
+		case mdCode:
+			code := s.r.NewStyle().Foreground(s.theme.Secondary).MaxWidth(width - 2)
+			var lines []string
+			for _, line := range block.lines {
+				lines = append(lines, "  "+code.Render(line))
+			}
+			out = append(out, strings.Join(lines, "\n"))
+		}
+	}
+	return s.r.NewStyle().Width(width).Render(strings.Join(out, "\n\n"))
+}
+
+// mdWrap renders spans as words packed into lines of at most width cells.
+// Each word is styled on its own, so no style runs across a line break.
+func (s styles) mdWrap(spans []mdSpan, width int, base lipgloss.Style) string {
+	var lines []string
+	var line, word strings.Builder
+	lineWidth, wordWidth := 0, 0
+
+	endWord := func() {
+		if wordWidth == 0 {
+			return
+		}
+		if lineWidth > 0 && lineWidth+1+wordWidth > width {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+		if lineWidth > 0 {
+			line.WriteString(" ")
+			lineWidth++
+		}
+		line.WriteString(word.String())
+		lineWidth += wordWidth
+		word.Reset()
+		wordWidth = 0
+	}
+
+	for _, sp := range spans {
+		st := base
+		switch {
+		case sp.code:
+			st = st.Foreground(s.theme.Secondary).Background(s.theme.TagBackground)
+		case sp.url != "":
+			st = st.Foreground(s.theme.Secondary).Underline(true)
+		}
+		if sp.strong {
+			st = st.Bold(true)
+		}
+		if sp.em {
+			st = st.Italic(true)
+		}
+
+		for i, part := range strings.Split(sp.text, " ") {
+			if i > 0 {
+				endWord()
+			}
+			if part == "" {
+				continue
+			}
+			rendered := st.Render(part)
+			if sp.url != "" {
+				rendered = hyperlink(sp.url, rendered)
+			}
+			word.WriteString(rendered)
+			wordWidth += lipgloss.Width(part)
+		}
+	}
+	endWord()
+	if lineWidth > 0 {
+		lines = append(lines, line.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// plainMarkdown is md as plain text: formatting markers dropped, each link
+// followed by its address, and paragraphs reflowed onto single lines.
+func plainMarkdown(md string) string {
+	var out []string
+	for _, block := range parseMarkdown(md) {
+		switch block.kind {
+		case mdParagraph, mdHeading:
+			out = append(out, plainInline(block.lines[0]))
+		case mdList:
+			var items []string
+			for i, item := range block.lines {
+				marker := "- "
+				if block.ordered {
+					marker = strconv.Itoa(i+1) + ". "
+				}
+				items = append(items, marker+plainInline(item))
+			}
+			out = append(out, strings.Join(items, "\n"))
+		case mdCode:
+			out = append(out, strings.Join(block.lines, "\n"))
+		}
+	}
+	return strings.Join(out, "\n\n")
+}
+
+func plainInline(text string) string {
+	var b strings.Builder
+	spans := parseInline(text)
+	for i, sp := range spans {
+		b.WriteString(sp.text)
+		// A link may be split over several spans; its address follows the last.
+		if sp.url != "" && (i == len(spans)-1 || spans[i+1].url != sp.url) && sp.text != sp.url {
+			b.WriteString(" (" + sp.url + ")")
+		}
+	}
+	return b.String()
+}
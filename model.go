@@ -1,6 +1,13 @@
 package main
 
 import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -8,78 +15,331 @@ import (
 
 var _ tea.Model = model{}
 
-var tabNames = []string{"About", "Experience", "Projects", "Education", "Skills", "Contact"}
+// visitor is the person behind a session. user is the SSH user name they
+// connected as and fingerprint their public key's SHA256 fingerprint, or ""
+// when they connected without a key. remember is set when their preferences
+// are saved, which needs a key they haven't asked to be forgotten by. admin
+// is set for keys in the admin keys file and ip is the address they connected
+// from. glyphs is the glyph set detected for their terminal, locale the
+// language code they asked for, accessible whether they asked for accessible
+// mode and slowLink whether their connection is too slow for the intro. last
+// is where the session notes the tab it is on, for visitors with a key, and
+// bidi where it notes that it took bidi reordering from the terminal.
+type visitor struct {
+	user        string
+	fingerprint string
+	remember    bool
+	admin       bool
+	ip          string
+	prefs       prefs
+	glyphs      glyphs
+	locale      string
+	accessible  bool
+	slowLink    bool
+	store       *store
+	last        *lastTab
+	bidi        *atomic.Bool
+}
+
+// savePrefs persists the visitor's preferences in the background.
+func (v visitor) savePrefs() tea.Cmd {
+	if !v.remember || v.store == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := v.store.savePrefs(v.fingerprint, v.prefs); err != nil {
+			log.Printf("Could not save preferences: %v", err)
+		}
+		return nil
+	}
+}
+
+// overlay is a panel drawn over the content area that takes the keyboard
+// while it is open.
+type overlay int
+
+const (
+	noOverlay overlay = iota
+	helpOverlay
+	themeOverlay
+	signOverlay    // the guestbook's sign form
+	contactOverlay // the contact form
+	editOverlay    // the Edit tab's form
+)
 
 type model struct {
-	activeTab int
-	hoverTab  int
-	tabs      []string
-	viewport  viewport.Model
-	width     int
-	height    int
-	ready     bool
-	styles    styles
+	activeTab   int
+	hoverTab    int
+	tier        layoutTier
+	zones       []zone // clickable regions of the current content
+	notice      string // shown in place of the footer hints
+	noticeID    int
+	skillCursor int
+	roleCursor  int // selected role in the wide Experience layout
+	posts       []Post
+	postCursor  int    // selected post, in the filtered list
+	postTag     string // tag the Writing list is filtered by, or ""
+	reading     bool   // the selected post is open on Writing
+	guestbook   []guestbookEntry
+	guestCursor int // selected guestbook entry; its page is the one shown
+	signInput   textinput.Model
+	signProblem string // why the sign form's message was turned down
+	contact     contactForm
+	edit        editor
+	timeline    bool
+	accessible  bool
+	intro       intro
+	overlay     overlay
+	themeCursor int
+	themeBefore Theme // restored if the theme menu is cancelled
+	locale      *locale
+	tabs        []Tab
+	portfolio   portfolio // what the tabs' built-in content is
+	content     int       // the version of the content file the tabs are from
+	app         *app
+	visitor     visitor
+	keys        keyMap
+	help        help.Model
+	viewport    viewport.Model
+	width       int
+	height      int
+	ready       bool
+	styles      styles
 }
 
-func newModel(width, height int, r *lipgloss.Renderer) model {
-	return model{
-		tabs:     tabNames,
-		hoverTab: -1,
-		width:    width,
-		height:   height,
-		styles:   newStyles(r),
+func newModel(width, height int, r *lipgloss.Renderer, a *app, v visitor) model {
+	keymap := a.keymap
+	if validKeymap(v.prefs.Keymap) {
+		keymap = v.prefs.Keymap
+	}
+	theme, ok := findTheme(a.themes, v.prefs.Theme)
+	if !ok {
+		theme = a.themeFor(r)
+	}
+	g, ok := findGlyphs(v.prefs.Glyphs)
+	if !ok {
+		g = v.glyphs
 	}
+	l, ok := findLocale(a.locales, v.locale)
+	if !ok {
+		l = english
+	}
+	posts := a.posts.list()
+	var guestbook []guestbookEntry
+	if a.guestbook {
+		guestbook = readGuestbook(a.store)
+	}
+	m := model{
+		locale:     l,
+		posts:      posts,
+		guestbook:  guestbook,
+		hoverTab:   -1,
+		width:      width,
+		height:     height,
+		tier:       tierFor(width, height),
+		app:        a,
+		visitor:    v,
+		accessible: v.accessible,
+		help:       help.New(),
+		content:    a.content.current(),
+	}
+	m.tabs = m.tabBar()
+	m.portfolio = portfolioOf(m.tabs)
+	m.keys = keyMapFor(keymap, len(m.tabs))
+	if v.admin {
+		m.edit = m.readEdits()
+	}
+	m.activeTab = m.resumeTab()
+	m = m.restyle(r, theme, g)
+	m.syncKeys()
+	m.intro.playing = wantsIntro(a, v, r.ColorProfile(), m.tier)
+	if !m.accessible {
+		m.notice = m.welcome()
+	}
+	return m
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	if m.accessible {
+		text := m.plainTab(m.activeTab)
+		if w := m.welcome(); w != "" {
+			text = "\n" + w + "\n" + text
+		}
+		return tea.Batch(tea.Println(text), m.watchPosts(), m.watchGuestbook(), m.watchContent())
+	}
+	if m.intro.playing {
+		return tea.Batch(introTick(), m.watchPosts(), m.watchGuestbook(), m.watchContent())
+	}
+	var welcome tea.Cmd
+	if m.notice != "" {
+		welcome = clearNotice(m.noticeID)
+	}
+	return tea.Batch(m.watchPosts(), m.watchGuestbook(), m.watchContent(), welcome)
 }
 
+// Update handles msg and notes the tab the visitor ends up on, which
+// saveLastTab saves when the session ends.
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := m.update(msg)
+	if m.visitor.last != nil {
+		m.visitor.last.title = m.tabs[m.activeTab].Title
+		m.visitor.last.remember = m.visitor.remember
+	}
+	return m, cmd
+}
+
+func (m model) update(msg tea.Msg) (model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if m.intro.playing {
+		var handled bool
+		if m, cmd, handled = m.updateIntro(msg); handled {
+			return m, cmd
+		}
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m = m.resize()
 
-		headerHeight := 4
-		footerHeight := 2
-		contentHeight := m.height - headerHeight - footerHeight
-		if contentHeight < 1 {
-			contentHeight = 1
-		}
+	case tea.KeyMsg:
+		switch {
+		case m.overlay == signOverlay:
+			return m.updateSignForm(msg)
 
-		if !m.ready {
-			m.viewport = viewport.New(m.width, contentHeight)
-			m.viewport.SetContent(m.currentTabContent())
-			m.ready = true
-		} else {
-			m.viewport.Width = m.width
-			m.viewport.Height = contentHeight
-			m.viewport.SetContent(m.currentTabContent())
-		}
+		case m.overlay == contactOverlay:
+			return m.updateContactForm(msg)
 
-	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c":
+		case m.overlay == editOverlay:
+			return m.updateEditForm(msg)
+
+		case key.Matches(msg, m.keys.Quit):
 			return m, tea.Quit
 
-		case "tab", "right", "l":
-			m.activeTab = (m.activeTab + 1) % len(m.tabs)
-			m.viewport.SetContent(m.currentTabContent())
+		case key.Matches(msg, m.keys.Forget):
+			return m.toggleRemember()
+
+		case m.accessible:
+			return m.updateAccessible(msg)
+
+		case m.overlay == themeOverlay:
+			return m.updateThemeMenu(msg)
+
+		case key.Matches(msg, m.keys.Help):
+			if m.overlay == helpOverlay {
+				return m.setOverlay(noOverlay), nil
+			}
+			return m.setOverlay(helpOverlay), nil
+
+		case key.Matches(msg, m.keys.Close):
+			if m.overlay == noOverlay {
+				return m.setReading(false), nil
+			}
+			return m.setOverlay(noOverlay), nil
+
+		case m.overlay != noOverlay:
+			return m, nil
+
+		case key.Matches(msg, m.keys.Next):
+			return m.setTab((m.activeTab + 1) % len(m.tabs)), nil
+
+		case key.Matches(msg, m.keys.Prev):
+			return m.setTab((m.activeTab - 1 + len(m.tabs)) % len(m.tabs)), nil
+
+		case key.Matches(msg, m.keys.Jump):
+			if i := int(msg.String()[0] - '1'); i < len(m.tabs) {
+				return m.setTab(i), nil
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Top):
 			m.viewport.GotoTop()
 			return m, nil
 
-		case "shift+tab", "left", "h":
-			m.activeTab = (m.activeTab - 1 + len(m.tabs)) % len(m.tabs)
-			m.viewport.SetContent(m.currentTabContent())
+		case key.Matches(msg, m.keys.Bottom):
+			m.viewport.GotoBottom()
+			return m, nil
+
+		case key.Matches(msg, m.keys.Keymap):
+			m.keys = keyMapFor(nextKeymap(m.keys.Name), len(m.tabs))
+			m.syncKeys()
+			m.visitor.prefs.Keymap = m.keys.Name
+			return m, m.visitor.savePrefs()
+
+		case key.Matches(msg, m.keys.Language):
+			m = m.setLocale(nextLocale(m.app.locales, m.locale))
+			return m, m.visitor.savePrefs()
+
+		case key.Matches(msg, m.keys.Theme):
+			i := m.themeIndex()
+			m = m.applyTheme(m.app.themes[(i+1)%len(m.app.themes)])
+			cmd := m.saveTheme()
+			return m, cmd
+
+		case key.Matches(msg, m.keys.ThemeMenu):
+			m.themeBefore = m.styles.theme
+			m.themeCursor = max(m.themeIndex(), 0)
+			return m.setOverlay(themeOverlay), nil
+
+		case key.Matches(msg, m.keys.Glyphs):
+			g := asciiGlyphs
+			if m.styles.g.Name == asciiGlyphs.Name {
+				g = unicodeGlyphs
+			}
+			m = m.restyle(m.styles.r, m.styles.theme, g)
+			m.visitor.prefs.Glyphs = g.Name
+			return m, m.visitor.savePrefs()
+
+		case key.Matches(msg, m.keys.Accessible):
+			m.accessible = true
+			m = m.setOverlay(noOverlay)
+			return m, tea.Sequence(tea.ExitAltScreen, tea.DisableMouse, tea.Println(m.plainTab(m.activeTab)))
+
+		case key.Matches(msg, m.keys.Timeline):
+			m.timeline = !m.timeline
+			m.syncKeys()
+			m.setContent()
 			m.viewport.GotoTop()
 			return m, nil
+
+		case key.Matches(msg, m.keys.NextItem, m.keys.PrevItem):
+			m = m.stepItem(key.Matches(msg, m.keys.NextItem))
+			m.setContent()
+			if m.reading {
+				m.viewport.GotoTop()
+			}
+			m.followEditCursor()
+			return m, nil
+
+		case key.Matches(msg, m.keys.Select) && m.tabs[m.activeTab].Type == sectionAdmin:
+			return m.openEditForm(), nil
+
+		case key.Matches(msg, m.keys.Select):
+			return m.setReading(true), nil
+
+		case key.Matches(msg, m.keys.Filter):
+			return m.setPostTag(m.nextPostTag()), nil
+
+		case key.Matches(msg, m.keys.Sign):
+			return m.openSignForm(), nil
+
+		case key.Matches(msg, m.keys.Hide, m.keys.Delete):
+			return m.moderate(key.Matches(msg, m.keys.Delete))
+
+		case key.Matches(msg, m.keys.Compose):
+			return m.openContactForm(), nil
 		}
 
 	case tea.MouseMsg:
-		if msg.Y <= 1 {
+		if m.overlay != noOverlay || m.accessible || m.tier == tierTooSmall {
+			return m, nil
+		}
+		if m.locale.rtl() {
+			msg.X = m.width - 1 - msg.X
+		}
+		if msg.Y < m.tabBarHeight() {
 			m.hoverTab = m.tabHitTest(msg.X)
 		} else {
 			m.hoverTab = -1
@@ -87,12 +347,44 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
 			if m.hoverTab >= 0 && m.hoverTab != m.activeTab {
-				m.activeTab = m.hoverTab
-				m.viewport.SetContent(m.currentTabContent())
-				m.viewport.GotoTop()
-				return m, nil
+				return m.setTab(m.hoverTab), nil
 			}
+			if z, ok := m.contentZoneAt(msg.X, msg.Y); ok {
+				return m.clickZone(z)
+			}
+		}
+
+	case postsMsg:
+		return m.setPosts(msg), m.watchPosts()
+
+	case contentMsg:
+		if int(msg) != m.content {
+			m.content = int(msg)
+			m = m.setTabs()
+		}
+		return m, m.watchContent()
+
+	case guestbookMsg:
+		m = m.setGuestbook(msg.entries)
+		if msg.tick {
+			return m, m.watchGuestbook()
+		}
+		return m, nil
+
+	case signedMsg:
+		return m.signed(msg)
+
+	case contactSentMsg:
+		return m.contactSent(msg)
+
+	case editSavedMsg:
+		return m.editSaved(msg)
+
+	case clearNoticeMsg:
+		if int(msg) == m.noticeID {
+			m.notice = ""
 		}
+		return m, nil
 	}
 
 	if m.ready {
@@ -103,44 +395,449 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) View() string {
+	if m.accessible {
+		return m.plainStatus()
+	}
+	if m.locale.rtl() && m.ready {
+		if m.visitor.bidi != nil {
+			m.visitor.bidi.Store(true)
+		}
+		return bidiExplicit + mirror(m.screen(), m.width)
+	}
+	return m.screen()
+}
+
+// screen draws the full layout, left to right.
+func (m model) screen() string {
 	if !m.ready {
-		return "\n  Initializing..."
+		return "\n  " + m.locale.T("Initializing...")
+	}
+	if m.tier == tierTooSmall {
+		return m.styles.renderTooSmall(m.width, m.height)
+	}
+	if m.intro.playing {
+		return m.renderIntro()
 	}
 
-	tabBar := m.styles.renderTabBar(m.tabs, m.activeTab, m.hoverTab, m.width)
+	tabBar := m.styles.renderTabBar(tabTitles(m.tabs, m.locale), m.activeTab, m.hoverTab, m.width)
 	content := m.styles.contentBox.Render(m.viewport.View())
-	footer := m.styles.renderFooter(m.width)
+	var overlayHints string // the footer's hints while an overlay has its own
+	keys := m.keys.mapHelp(func(s string) string { return m.styles.isolate(m.styles.g.Text(m.locale.T(s))) })
+	switch m.overlay {
+	case helpOverlay:
+		closeHint := m.locale.F("Press %s or %s to close", keys.Help.Help().Key, keys.Close.Help().Key)
+		content = m.styles.renderHelpOverlay(m.help.FullHelpView(keys.FullHelp()), closeHint, m.width, lipgloss.Height(content))
+	case themeOverlay:
+		up, down, sel, cancel := keys.Up, keys.Down, keys.Select, keys.Close
+		up.SetHelp(up.Help().Key, m.locale.T("previous"))
+		down.SetHelp(down.Help().Key, m.locale.T("next"))
+		sel.SetHelp(sel.Help().Key, m.locale.T("keep"))
+		cancel.SetHelp(cancel.Help().Key, m.locale.T("cancel"))
+		hint := m.help.ShortHelpView([]key.Binding{up, down, sel, cancel})
+		content = m.styles.renderThemeMenu(m.app.themes, m.themeCursor, hint, content, m.width, lipgloss.Height(content))
+	case signOverlay:
+		sel, cancel := keys.Select, keys.Close
+		sel.SetHelp(sel.Help().Key, m.locale.T("sign"))
+		cancel.SetHelp(cancel.Help().Key, m.locale.T("cancel"))
+		signer := m.locale.F("Signing as %s, key %s", m.visitor.user, shortFingerprint(m.visitor.fingerprint))
+		hint := m.help.ShortHelpView([]key.Binding{sel, cancel})
+		content = m.styles.renderSignForm(m.signInput.View(), signer, m.signProblem, hint, m.width, lipgloss.Height(content))
+	case contactOverlay:
+		if m.contact.sent {
+			done := keys.Close
+			done.SetHelp(done.Help().Key, m.locale.T("close"))
+			content = m.styles.renderContactSent(m.sentText(), m.help.ShortHelpView([]key.Binding{done}), m.width, lipgloss.Height(content))
+			break
+		}
+		cancel := keys.Close
+		cancel.SetHelp(cancel.Help().Key, m.locale.T("cancel"))
+		hint := m.help.ShortHelpView([]key.Binding{keys.NextField, keys.Send, cancel})
+		content = m.styles.renderContactForm(m.contact, hint, m.width, lipgloss.Height(content))
+	case editOverlay:
+		save, preview, cancel := keys.Send, keys.Preview, keys.Close
+		save.SetHelp(save.Help().Key, m.locale.T("save"))
+		cancel.SetHelp(cancel.Help().Key, m.locale.T("cancel"))
+		if m.edit.preview != nil {
+			preview.SetHelp(preview.Help().Key, m.locale.T("edit"))
+			cancel.SetHelp(cancel.Help().Key, m.locale.T("back"))
+			label := m.styles.accentText.Render(m.styles.g.Text(m.locale.T("Preview")))
+			overlayHints = label + m.help.Styles.ShortSeparator.Render(m.help.ShortSeparator) +
+				m.help.ShortHelpView([]key.Binding{preview, save, cancel})
+			if m.edit.problem != "" {
+				overlayHints = m.styles.r.NewStyle().Foreground(m.styles.theme.Pink).Render(m.styles.g.Text(m.edit.problem))
+			}
+			break
+		}
+		h := m.help
+		h.Width = min(m.width-16, 72)
+		hint := h.ShortHelpView([]key.Binding{keys.NextField, preview, save, cancel})
+		content = m.styles.renderEditForm(m.edit, hint, m.width, lipgloss.Height(content))
+	}
+	// The reader's page position leads the hints.
+	var page string
+	if m.tabs[m.activeTab].Type == sectionPosts && m.reading {
+		page = m.styles.dimText.Render(m.postPage()) + m.help.Styles.ShortSeparator.Render(m.help.ShortSeparator)
+	}
+	m.help.Width = m.width - footerCopyrightWidth - lipgloss.Width(page)
+	if m.tier == tierCompact {
+		m.help.Width = m.width - lipgloss.Width(page)
+	}
+	hints := page + m.help.ShortHelpView(keys.ShortHelp())
+	if overlayHints != "" {
+		hints = overlayHints
+	}
+	if m.notice != "" {
+		hints = m.styles.accentText.Render(m.styles.g.Text(m.notice))
+	}
+	footer := m.styles.renderFooter(hints, m.portfolio.profile.Name, m.width)
 
 	return lipgloss.JoinVertical(lipgloss.Left, tabBar, content, footer)
 }
 
+// setTab switches to tab i and shows its content from the top.
+func (m model) setTab(i int) model {
+	m.activeTab = i
+	m.syncKeys()
+	m.setContent()
+	m.viewport.GotoTop()
+	return m
+}
+
+// stepItem moves the current tab's cursor to the next item, or the previous
+// one, wrapping around: skills on Skills, roles on Experience, posts on
+// Writing and what to edit on Edit.
+func (m model) stepItem(next bool) model {
+	cursor, n := &m.skillCursor, len(m.portfolio.allSkills())
+	switch m.tabs[m.activeTab].Type {
+	case sectionTimeline:
+		cursor, n = &m.roleCursor, len(m.portfolio.experiences)
+	case sectionPosts:
+		cursor, n = &m.postCursor, len(m.filteredPosts())
+	case sectionGuestbook:
+		return m.stepGuestbook(next)
+	case sectionAdmin:
+		cursor, n = &m.edit.cursor, len(m.edit.targets)
+	}
+	if n == 0 {
+		return m
+	}
+	if next {
+		*cursor = (*cursor + 1) % n
+	} else {
+		*cursor = (*cursor - 1 + n) % n
+	}
+	return m
+}
+
+// setOverlay opens o, or closes any overlay for noOverlay.
+func (m model) setOverlay(o overlay) model {
+	m.overlay = o
+	m.syncKeys()
+	return m
+}
+
+// updateThemeMenu handles keys while the theme menu is open. Moving the
+// cursor previews a theme, select keeps it and close restores the theme the
+// menu was opened with.
+func (m model) updateThemeMenu(msg tea.KeyMsg) (model, tea.Cmd) {
+	n := len(m.app.themes)
+	switch {
+	case key.Matches(msg, m.keys.Up, m.keys.Prev):
+		m.themeCursor = (m.themeCursor - 1 + n) % n
+		return m.applyTheme(m.app.themes[m.themeCursor]), nil
+	case key.Matches(msg, m.keys.Down, m.keys.Next):
+		m.themeCursor = (m.themeCursor + 1) % n
+		return m.applyTheme(m.app.themes[m.themeCursor]), nil
+	case key.Matches(msg, m.keys.Select):
+		cmd := m.saveTheme()
+		return m.setOverlay(noOverlay), cmd
+	case key.Matches(msg, m.keys.Close, m.keys.ThemeMenu):
+		return m.applyTheme(m.themeBefore).setOverlay(noOverlay), nil
+	}
+	return m, nil
+}
+
+// setLocale switches the interface to l, with the tabs of l's content file
+// if it has one.
+func (m model) setLocale(l *locale) model {
+	m.locale = l
+	m.visitor.prefs.Locale = l.Tag
+	m = m.setTabs()
+	m = m.restyle(m.styles.r, m.styles.theme, m.styles.g)
+	m.viewport.GotoTop()
+	return m
+}
+
+// contentMsg carries the content file's version.
+type contentMsg int
+
+// watchContent checks the content file's version after contentRefresh.
+func (m model) watchContent() tea.Cmd {
+	if m.app.content.path == "" {
+		return nil
+	}
+	c := m.app.content
+	return tea.Tick(contentRefresh, func(time.Time) tea.Msg { return contentMsg(c.current()) })
+}
+
+// tabBar is the tabs for the visitor's language: the content's, the
+// Guestbook, Writing when there are posts and Edit for admins.
+func (m model) tabBar() []Tab {
+	tabs := tabsFor(m.app.tabsIn(m.locale), m.posts)
+	if m.visitor.admin {
+		tabs = append(tabs, Tab{Title: editTab, Type: sectionAdmin})
+	}
+	return tabs
+}
+
+// setTabs rebuilds the tab bar, staying on the current tab if it is still
+// there and going back to the first one if not, and keeps the skill and role
+// cursors on the portfolio it shows.
+func (m model) setTabs() model {
+	active := m.tabs[m.activeTab].Title
+	m.tabs = m.tabBar()
+	m.activeTab = 0
+	for i, t := range m.tabs {
+		if t.Title == active {
+			m.activeTab = i
+		}
+	}
+	m.portfolio = portfolioOf(m.tabs)
+	m.skillCursor = min(m.skillCursor, max(len(m.portfolio.allSkills())-1, 0))
+	m.roleCursor = min(m.roleCursor, max(len(m.portfolio.experiences)-1, 0))
+	if m.visitor.admin {
+		m.edit = m.readEdits()
+	}
+	m.keys = keyMapFor(m.keys.Name, len(m.tabs))
+	m.syncKeys()
+	if m.ready {
+		m.setContent()
+	}
+	return m
+}
+
+// applyTheme switches to theme t, keeping the glyph set.
+func (m model) applyTheme(t Theme) model {
+	return m.restyle(m.styles.r, t, m.styles.g)
+}
+
+// restyle rebuilds every style from t and g and re-renders the current tab.
+func (m model) restyle(r *lipgloss.Renderer, t Theme, g glyphs) model {
+	m.styles = newStyles(r, t, g, m.locale).sized(m.tier)
+	m.help.Styles = m.styles.helpStyles()
+	m.help.ShortSeparator = " " + g.Bull + " "
+	m.help.Ellipsis = g.Ellipsis
+	if m.ready {
+		m.setContent()
+	}
+	return m
+}
+
+// themeIndex is the position of the current theme in the app's theme list,
+// or -1 if it is not there.
+func (m model) themeIndex() int {
+	for i, t := range m.app.themes {
+		if t.Name == m.styles.theme.Name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *model) saveTheme() tea.Cmd {
+	m.visitor.prefs.Theme = m.styles.theme.Name
+	return m.visitor.savePrefs()
+}
+
+// syncKeys enables the bindings that apply to the current tab and hands the
+// scrolling bindings to the viewport.
+func (m *model) syncKeys() {
+	roles := m.tier == tierWide && !m.timeline && !m.accessible
+	tab := m.tabs[m.activeTab]
+	m.keys = m.keys.forTab(tab, m.overlay, m.accessible, roles, tab.Type == sectionPosts && m.reading, m.visitor.fingerprint != "", m.visitor.remember, m.visitor.admin, m.app.inbox != nil)
+	m.viewport.KeyMap = m.keys.viewportKeyMap()
+}
+
+// tabHitTest is the tab under column x of the tab bar, or -1.
 func (m model) tabHitTest(x int) int {
-	for i, name := range m.tabs {
-		tabWidth := len(name) + 4 // padding(0,2) adds 4
-		if x < tabWidth {
+	return tabAt(m.styles.layoutTabs(tabTitles(m.tabs, m.locale), m.activeTab, m.width), x)
+}
+
+// resize picks the layout tier for the window size and fits the viewport
+// between the tab bar and the footer.
+func (m model) resize() model {
+	m.tier = tierFor(m.width, m.height)
+	m.styles = m.styles.sized(m.tier)
+	m.syncKeys()
+
+	footer := m.styles.renderFooter("", m.portfolio.profile.Name, m.width)
+	contentHeight := m.height - m.tabBarHeight() - lipgloss.Height(footer) - m.styles.contentBox.GetVerticalPadding()
+	contentHeight = max(contentHeight, 1)
+
+	if !m.ready {
+		m.viewport = viewport.New(m.width, contentHeight)
+		m.viewport.KeyMap = m.keys.viewportKeyMap()
+		m.ready = true
+	} else {
+		m.viewport.Width = m.width
+		m.viewport.Height = contentHeight
+	}
+	m.setContent()
+	return m
+}
+
+// tabBarHeight is the number of lines the tab bar takes.
+func (m model) tabBarHeight() int {
+	return lipgloss.Height(m.styles.renderTabBar(tabTitles(m.tabs, m.locale), m.activeTab, m.hoverTab, m.width))
+}
+
+// setContent renders the current tab into the viewport and records where its
+// clickable elements landed.
+func (m *model) setContent() {
+	content, zones := scanZones(m.currentTabContent())
+	m.zones = zones
+	m.viewport.SetContent(content)
+}
+
+// contentZoneAt is the clickable zone under screen cell (x, y), if any.
+func (m model) contentZoneAt(x, y int) (zone, bool) {
+	top := m.tabBarHeight() + m.styles.contentBox.GetPaddingTop()
+	left := m.styles.contentBox.GetPaddingLeft()
+	if y < top || y >= top+m.viewport.Height {
+		return zone{}, false
+	}
+	return zoneAt(m.zones, x-left, y-top+m.viewport.YOffset)
+}
+
+// clickZone acts on a click in the content: a skill tag selects that skill
+// on the Skills tab, a role selects it in the wide Experience layout, a post
+// opens it and a post tag filters by it, and a project card or contact
+// copies its address.
+func (m model) clickZone(z zone) (model, tea.Cmd) {
+	switch z.kind {
+	case zoneTag:
+		m.skillCursor = z.index
+		if i := m.builtinTab(sectionTags); i >= 0 && i != m.activeTab {
+			return m.setTab(i), nil
+		}
+		m.setContent()
+	case zoneRole:
+		m.roleCursor = z.index
+		m.setContent()
+	case zonePost:
+		m.postCursor = z.index
+		return m.setReading(true), nil
+	case zonePostTag:
+		return m.setPostTag(append([]string{""}, postTags(m.posts)...)[z.index]), nil
+	case zoneEdit:
+		m.edit.cursor = z.index
+		return m.openEditForm(), nil
+	case zoneCard:
+		if u := m.tabs[m.activeTab].cards(m.portfolio)[z.index].link(); u != "" {
+			return m.copyText(u)
+		}
+	case zoneContact:
+		c := m.tabs[m.activeTab].contacts(m.portfolio)[z.index]
+		if u := c.webURL(); u != "" {
+			return m.copyText(u)
+		}
+		return m.copyText(c.Value)
+	}
+	return m, nil
+}
+
+// noticeDuration is how long a footer notice stays up.
+const noticeDuration = 3 * time.Second
+
+// clearNoticeMsg clears the footer notice with the given ID, unless a newer
+// one has replaced it.
+type clearNoticeMsg int
+
+// copyText puts text on the visitor's clipboard with OSC 52, which most
+// terminals support over SSH, and says so in the footer.
+func (m model) copyText(text string) (model, tea.Cmd) {
+	out := m.styles.r.Output()
+	m, cmd := m.setNotice(m.locale.F("Copied %s", text))
+	return m, tea.Batch(func() tea.Msg { out.Copy(text); return nil }, cmd)
+}
+
+// setNotice shows text in the footer for noticeDuration.
+func (m model) setNotice(text string) (model, tea.Cmd) {
+	m.notice = text
+	m.noticeID++
+	return m, clearNotice(m.noticeID)
+}
+
+// clearNotice clears notice id after noticeDuration.
+func clearNotice(id int) tea.Cmd {
+	return tea.Tick(noticeDuration, func(time.Time) tea.Msg { return clearNoticeMsg(id) })
+}
+
+// builtinTab is the position of the first tab of type typ that shows the
+// portfolio's own content, or -1.
+func (m model) builtinTab(typ string) int {
+	for i, t := range m.tabs {
+		if t.Type == typ && t.builtin() {
 			return i
 		}
-		x -= tabWidth
 	}
 	return -1
 }
 
+// currentTabContent renders the active tab, in characters the glyph set can
+// display.
 func (m model) currentTabContent() string {
+	return m.styles.g.Text(m.renderTab())
+}
+
+// renderTab renders the active tab, or the tab the edit form would save
+// while it is previewed.
+func (m model) renderTab() string {
+	if m.overlay == editOverlay && m.edit.preview != nil {
+		return m.render(*m.edit.preview, m.edit.previewPortfolio)
+	}
+	return m.render(m.tabs[m.activeTab], m.portfolio)
+}
+
+// render renders tab t, with p as the built-in content.
+func (m model) render(t Tab, p portfolio) string {
 	s := m.styles
 	w := m.width
-	switch m.activeTab {
-	case 0:
-		return renderAbout(s, w)
-	case 1:
-		return renderExperience(s, w)
-	case 2:
-		return renderProjects(s, w)
-	case 3:
-		return renderEducation(s, w)
-	case 4:
-		return renderSkills(s, w)
-	case 5:
-		return renderContact(s, w)
+	heading := m.locale.T(t.heading())
+	switch t.Type {
+	case sectionBio:
+		return renderAbout(s, t.profile(p), w, m.app.fonts)
+	case sectionTimeline:
+		if !t.builtin() {
+			return renderEntries(s, w, heading, t.Entries)
+		}
+		if m.timeline {
+			return renderTimeline(s, p.experiences, w)
+		}
+		return renderExperience(s, p, w, m.roleCursor)
+	case sectionCards:
+		return renderProjects(s, p, w, heading, t.cards(p))
+	case sectionTags:
+		if !t.builtin() {
+			return renderTagGroups(s, w, heading, t.Groups)
+		}
+		return renderSkills(s, p, w, m.skillCursor)
+	case sectionList:
+		if !t.builtin() {
+			return renderList(s, w, heading, t.Items)
+		}
+		return renderContact(s, p.contacts, w, m.contactHint())
+	case sectionMarkdown:
+		return renderMarkdownTab(s, w, heading, t.Body)
+	case sectionPosts:
+		if posts := m.filteredPosts(); m.reading {
+			return renderPost(s, w, posts[m.postCursor])
+		}
+		return renderWriting(s, w, m.posts, m.postTag, m.postCursor)
+	case sectionGuestbook:
+		return renderGuestbook(s, w, m.guestbookEntries(), m.guestCursor, m.visitor.admin, m.signHint())
+	case sectionAdmin:
+		return renderEditList(s, w, m.edit.targets, m.edit.cursor, m.editHint())
 	default:
 		return ""
 	}
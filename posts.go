@@ -0,0 +1,192 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Post is a Markdown file from the posts directory. Its front matter, a
+// block of "key: value" lines between "---" lines at the top of the file,
+// sets the title, date and tags:
+//
+//	---
+//	title: Shipping a TUI over SSH
+//	date: 2025-03-14
+//	tags: [go, ssh]
+//	---
+//
+// Without a title the first heading or the file name is used, and without a
+// date the file's modification time.
+type Post struct {
+	File  string
+	Title string
+	Date  time.Time
+	Tags  []string
+	Body  string
+}
+
+// postLibrary is the posts directory, shared by every session. Files are
+// parsed once and again only when they change, so listing it is cheap
+// enough to repeat every few seconds.
+type postLibrary struct {
+	dir   string
+	mu    sync.Mutex
+	cache map[string]cachedPost
+}
+
+type cachedPost struct {
+	modTime time.Time
+	size    int64
+	post    Post
+}
+
+func newPostLibrary(dir string) *postLibrary {
+	return &postLibrary{dir: dir, cache: map[string]cachedPost{}}
+}
+
+// list returns the posts, newest first. A missing directory has no posts.
+func (l *postLibrary) list() []Post {
+	if l == nil {
+		return nil
+	}
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	seen := map[string]bool{}
+	var posts []Post
+	for _, e := range entries {
+		if e.IsDir() || !strings.EqualFold(filepath.Ext(e.Name()), ".md") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		name := e.Name()
+		seen[name] = true
+		c, ok := l.cache[name]
+		if !ok || !c.modTime.Equal(info.ModTime()) || c.size != info.Size() {
+			data, err := os.ReadFile(filepath.Join(l.dir, name))
+			if err != nil {
+				continue
+			}
+			c = cachedPost{info.ModTime(), info.Size(), parsePost(name, string(data), info.ModTime())}
+			l.cache[name] = c
+		}
+		posts = append(posts, c.post)
+	}
+	for name := range l.cache {
+		if !seen[name] {
+			delete(l.cache, name)
+		}
+	}
+
+	sort.SliceStable(posts, func(i, j int) bool {
+		if !posts[i].Date.Equal(posts[j].Date) {
+			return posts[i].Date.After(posts[j].Date)
+		}
+		return posts[i].File < posts[j].File
+	})
+	return posts
+}
+
+// parsePost reads a post's front matter and body.
+func parsePost(file, text string, modTime time.Time) Post {
+	p := Post{File: file, Date: modTime}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+
+	if rest, ok := strings.CutPrefix(text, "---\n"); ok {
+		if front, body, ok := strings.Cut(rest, "\n---"); ok {
+			text = strings.TrimPrefix(strings.TrimLeft(body, "-"), "\n")
+			for _, line := range strings.Split(front, "\n") {
+				k, v, ok := strings.Cut(line, ":")
+				if !ok {
+					continue
+				}
+				v = strings.Trim(strings.TrimSpace(v), `"'`)
+				switch strings.ToLower(strings.TrimSpace(k)) {
+				case "title":
+					p.Title = v
+				case "date":
+					for _, layout := range []string{"2006-01-02", time.RFC3339, "2006-01-02 15:04"} {
+						if t, err := time.Parse(layout, v); err == nil {
+							p.Date = t
+							break
+						}
+					}
+				case "tags":
+					for _, tag := range strings.Split(strings.Trim(v, "[]"), ",") {
+						if tag = strings.Trim(strings.TrimSpace(tag), `"'`); tag != "" {
+							p.Tags = append(p.Tags, tag)
+						}
+					}
+				}
+			}
+		}
+	}
+	p.Body = strings.TrimSpace(text)
+
+	if p.Title == "" {
+		for _, b := range parseMarkdown(p.Body) {
+			if b.kind == mdHeading {
+				p.Title = plainInline(b.lines[0])
+				break
+			}
+		}
+	}
+	if p.Title == "" {
+		p.Title = strings.TrimSuffix(file, filepath.Ext(file))
+	}
+	return p
+}
+
+// excerpt is the post's first paragraph as plain text.
+func (p Post) excerpt() string {
+	for _, b := range parseMarkdown(p.Body) {
+		if b.kind == mdParagraph {
+			return plainInline(b.lines[0])
+		}
+	}
+	return ""
+}
+
+// postTags is every tag used by posts, sorted.
+func postTags(posts []Post) []string {
+	seen := map[string]bool{}
+	var tags []string
+	for _, p := range posts {
+		for _, t := range p.Tags {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// filterPosts returns the posts tagged tag, or all of them for "".
+func filterPosts(posts []Post, tag string) []Post {
+	if tag == "" {
+		return posts
+	}
+	var out []Post
+	for _, p := range posts {
+		for _, t := range p.Tags {
+			if t == tag {
+				out = append(out, p)
+				break
+			}
+		}
+	}
+	return out
+}
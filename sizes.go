@@ -0,0 +1,60 @@
+package main
+
+import "github.com/charmbracelet/lipgloss"
+
+// layoutTier is how much room the terminal leaves for the layout. It is
+// re-evaluated on every resize.
+type layoutTier int
+
+const (
+	tierNormal   layoutTier = iota
+	tierWide                // two columns: lists beside details, grids of cards
+	tierCompact             // no banner, a one-line tab selector and footer, less padding
+	tierTooSmall            // only a request to enlarge the terminal
+)
+
+// Below the compact size the normal layout's chrome would leave too little
+// room for content; below the minimum even the compact one would. From
+// wideWidth up, a single column capped at readable width would leave most of
+// the screen empty.
+const (
+	wideWidth     = 160
+	compactWidth  = 50
+	compactHeight = 16
+	minWidth      = 20
+	minHeight     = 6
+)
+
+func tierFor(width, height int) layoutTier {
+	switch {
+	case width < minWidth || height < minHeight:
+		return tierTooSmall
+	case width < compactWidth || height < compactHeight:
+		return tierCompact
+	case width >= wideWidth:
+		return tierWide
+	default:
+		return tierNormal
+	}
+}
+
+// sized returns s adjusted for the layout tier.
+func (s styles) sized(tier layoutTier) styles {
+	s.tier = tier
+	if tier == tierCompact {
+		s.contentBox = s.contentBox.Padding(0, 1)
+	} else {
+		s.contentBox = s.contentBox.Padding(1, 2)
+	}
+	return s
+}
+
+// renderTooSmall asks the visitor to enlarge their terminal.
+func (s styles) renderTooSmall(width, height int) string {
+	line := s.r.NewStyle().Width(width).Align(lipgloss.Center)
+	msg := lipgloss.JoinVertical(lipgloss.Center,
+		line.Inherit(s.accentText).Render(s.l.T("Terminal too small")),
+		line.Inherit(s.mutedText).Render(s.g.Text(s.l.F("%d×%d, need %d×%d", width, height, minWidth, minHeight))),
+	)
+	return s.r.Place(width, height, lipgloss.Center, lipgloss.Center, msg)
+}
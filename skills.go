@@ -0,0 +1,104 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// highlightRef points at a single experience highlight that mentions a skill.
+type highlightRef struct {
+	Experience Experience
+	Highlight  string
+}
+
+// skillEvidence collects everywhere a skill shows up in the portfolio.
+type skillEvidence struct {
+	Highlights []highlightRef
+	Projects   []Project
+}
+
+func (e skillEvidence) empty() bool {
+	return len(e.Highlights) == 0 && len(e.Projects) == 0
+}
+
+// allSkills flattens p's skill groups into the order they appear on the
+// Skills tab.
+func (p portfolio) allSkills() []string {
+	var out []string
+	for _, g := range p.skillGroups {
+		out = append(out, g.Skills...)
+	}
+	return out
+}
+
+// skillTerms returns the terms that count as a mention of skill: the skill
+// name itself plus any aliases from skillAliases.
+func skillTerms(skill string) []string {
+	return append([]string{skill}, skillAliases[skill]...)
+}
+
+// skillIndex is the position in allSkills of the skill named term, or else
+// of the first skill that has term as an alias.
+func (p portfolio) skillIndex(term string) (int, bool) {
+	skills := p.allSkills()
+	for i, sk := range skills {
+		if strings.EqualFold(sk, term) {
+			return i, true
+		}
+	}
+	for i, sk := range skills {
+		for _, alias := range skillAliases[sk] {
+			if strings.EqualFold(alias, term) {
+				return i, true
+			}
+		}
+	}
+	return -1, false
+}
+
+// mentionRes caches mentionRe's expressions by term.
+var mentionRes sync.Map
+
+// mentionRe matches term as a whole word, case-insensitively, so that "SQL"
+// does not match inside "SQLAlchemy" and "Git" does not match "GitHub".
+func mentionRe(term string) *regexp.Regexp {
+	if re, ok := mentionRes.Load(term); ok {
+		return re.(*regexp.Regexp)
+	}
+	re := regexp.MustCompile(`(?i)(^|[^\w+])` + regexp.QuoteMeta(term) + `($|[^\w+])`)
+	mentionRes.Store(term, re)
+	return re
+}
+
+func mentions(text string, res []*regexp.Regexp) bool {
+	for _, re := range res {
+		if re.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// evidenceFor finds the experience highlights and projects that use skill.
+func (p portfolio) evidenceFor(skill string) skillEvidence {
+	var res []*regexp.Regexp
+	for _, t := range skillTerms(skill) {
+		res = append(res, mentionRe(t))
+	}
+
+	var ev skillEvidence
+	for _, exp := range p.sortedExperiences() {
+		for _, h := range exp.Highlights {
+			if mentions(h, res) {
+				ev.Highlights = append(ev.Highlights, highlightRef{Experience: exp, Highlight: h})
+			}
+		}
+	}
+	for _, proj := range p.projects {
+		if mentions(strings.Join(proj.Tech, ", "), res) {
+			ev.Projects = append(ev.Projects, proj)
+		}
+	}
+	return ev
+}
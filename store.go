@@ -0,0 +1,337 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	prefsBucket     = []byte("prefs")
+	guestbookBucket = []byte("guestbook")
+	signedBucket    = []byte("guestbook-signed") // fingerprint to last signing time
+	optOutBucket    = []byte("opt-out")          // fingerprints not to remember
+)
+
+// store is the server's on-disk state, kept in a single bbolt file. The
+// guestbook is cached until it next changes, so that sessions refreshing it
+// share one read.
+type store struct {
+	db *bolt.DB
+
+	mu      sync.Mutex
+	entries []guestbookEntry // nil until read
+}
+
+// prefs are what is remembered about a visitor by public key: the settings
+// they chose, how many times they have visited, when they last did and the
+// tab they were on.
+type prefs struct {
+	Keymap    string    `json:"keymap,omitempty"`
+	Theme     string    `json:"theme,omitempty"`
+	Glyphs    string    `json:"glyphs,omitempty"`
+	Locale    string    `json:"locale,omitempty"`
+	Visits    int       `json:"visits,omitempty"`
+	LastVisit time.Time `json:"last_visit"`
+	Tab       string    `json:"tab,omitempty"` // title of the tab they were on
+}
+
+func openStore(path string) (*store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{prefsBucket, guestbookBucket, signedBucket, optOutBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &store{db: db}, nil
+}
+
+func (s *store) Close() error {
+	return s.db.Close()
+}
+
+// savePrefs saves the settings in p for a key fingerprint, leaving its
+// visits and tab as they are, unless it opted out of being remembered.
+func (s *store) savePrefs(fingerprint string, p prefs) error {
+	return s.updatePrefs(fingerprint, func(saved *prefs) {
+		saved.Keymap, saved.Theme, saved.Glyphs, saved.Locale = p.Keymap, p.Theme, p.Glyphs, p.Locale
+	})
+}
+
+// saveTab saves the tab a key fingerprint's session ended on, unless it
+// opted out of being remembered.
+func (s *store) saveTab(fingerprint, tab string) error {
+	return s.updatePrefs(fingerprint, func(saved *prefs) { saved.Tab = tab })
+}
+
+// updatePrefs applies fn to a key fingerprint's saved preferences in one
+// transaction, so that sessions of the same key only change what they mean
+// to, and does nothing if it opted out.
+func (s *store) updatePrefs(fingerprint string, fn func(*prefs)) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(optOutBucket).Get([]byte(fingerprint)) != nil {
+			return nil
+		}
+		b := tx.Bucket(prefsBucket)
+		var p prefs
+		if v := b.Get([]byte(fingerprint)); v != nil {
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+		}
+		fn(&p)
+		v, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(fingerprint), v)
+	})
+}
+
+// visit counts a visit at now by a visitor's key fingerprint and returns
+// their preferences with it, unless they opted out of being remembered.
+func (s *store) visit(fingerprint string, now time.Time) (p prefs, optedOut bool, err error) {
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(optOutBucket).Get([]byte(fingerprint)) != nil {
+			optedOut = true
+			return nil
+		}
+		b := tx.Bucket(prefsBucket)
+		if v := b.Get([]byte(fingerprint)); v != nil {
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+		}
+		p.Visits++
+		p.LastVisit = now
+		v, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(fingerprint), v)
+	})
+	return p, optedOut, err
+}
+
+// forget deletes what is remembered about a key fingerprint and keeps it
+// from being remembered again until remember is called.
+func (s *store) forget(fingerprint string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(prefsBucket).Delete([]byte(fingerprint)); err != nil {
+			return err
+		}
+		return tx.Bucket(optOutBucket).Put([]byte(fingerprint), []byte{})
+	})
+}
+
+// remember undoes forget, saving p for the fingerprint from now on.
+func (s *store) remember(fingerprint string, p prefs) error {
+	v, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(optOutBucket).Delete([]byte(fingerprint)); err != nil {
+			return err
+		}
+		return tx.Bucket(prefsBucket).Put([]byte(fingerprint), v)
+	})
+}
+
+// prune deletes the preferences of visitors who haven't visited in keep,
+// and guestbook signing times older than signEvery, which no longer hold
+// anyone back. Preferences saved before visits were counted have no last
+// visit; it is set to now so that their time starts running. It returns how
+// many visitors it forgot.
+func (s *store) prune(now time.Time, keep, signEvery time.Duration) (int, error) {
+	var n int
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(prefsBucket)
+		var stale [][]byte
+		stamp := map[string][]byte{}
+		err := b.ForEach(func(k, v []byte) error {
+			var p prefs
+			if err := json.Unmarshal(v, &p); err != nil {
+				return err
+			}
+			switch {
+			case p.LastVisit.IsZero():
+				p.LastVisit = now
+				v, err := json.Marshal(p)
+				if err != nil {
+					return err
+				}
+				stamp[string(k)] = v
+			case keep > 0 && now.Sub(p.LastVisit) > keep:
+				stale = append(stale, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for k, v := range stamp {
+			if err := b.Put([]byte(k), v); err != nil {
+				return err
+			}
+		}
+		for _, k := range stale {
+			if err := b.Delete(k); err != nil {
+				return err
+			}
+		}
+		n = len(stale)
+
+		signed := tx.Bucket(signedBucket)
+		var expired [][]byte
+		err = signed.ForEach(func(k, v []byte) error {
+			var last time.Time
+			if err := last.UnmarshalText(v); err != nil {
+				return err
+			}
+			if now.Sub(last) > signEvery {
+				expired = append(expired, k)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range expired {
+			if err := signed.Delete(k); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return n, err
+}
+
+// guestbook returns every guestbook entry, hidden ones included, newest
+// first. The slice is shared and must not be modified.
+func (s *store) guestbook() ([]guestbookEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.entries != nil {
+		return s.entries, nil
+	}
+	entries := []guestbookEntry{}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(guestbookBucket).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			var e guestbookEntry
+			if err := json.Unmarshal(v, &e); err != nil {
+				return err
+			}
+			e.ID = binary.BigEndian.Uint64(k)
+			entries = append(entries, e)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.entries = entries
+	return entries, nil
+}
+
+// updateGuestbook runs fn in a write transaction and drops the cached
+// guestbook, which fn may have changed.
+func (s *store) updateGuestbook(fn func(tx *bolt.Tx) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = nil
+	return s.db.Update(fn)
+}
+
+// sign adds e to the guestbook unless its key signed less than every ago,
+// in which case it returns how long is left to wait.
+func (s *store) sign(e guestbookEntry, every time.Duration) (time.Duration, error) {
+	var wait time.Duration
+	err := s.updateGuestbook(func(tx *bolt.Tx) error {
+		signed := tx.Bucket(signedBucket)
+		if v := signed.Get([]byte(e.Fingerprint)); v != nil {
+			var last time.Time
+			if err := last.UnmarshalText(v); err != nil {
+				return err
+			}
+			if wait = last.Add(every).Sub(e.Time); wait > 0 {
+				return nil
+			}
+			wait = 0
+		}
+		stamp, err := e.Time.MarshalText()
+		if err != nil {
+			return err
+		}
+		if err := signed.Put([]byte(e.Fingerprint), stamp); err != nil {
+			return err
+		}
+
+		b := tx.Bucket(guestbookBucket)
+		id, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		v, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		return b.Put(entryKey(id), v)
+	})
+	return wait, err
+}
+
+// setHidden hides or shows guestbook entry id.
+func (s *store) setHidden(id uint64, hidden bool) error {
+	return s.updateGuestbook(func(tx *bolt.Tx) error {
+		b := tx.Bucket(guestbookBucket)
+		v := b.Get(entryKey(id))
+		if v == nil {
+			return nil
+		}
+		var e guestbookEntry
+		if err := json.Unmarshal(v, &e); err != nil {
+			return err
+		}
+		e.Hidden = hidden
+		v, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		return b.Put(entryKey(id), v)
+	})
+}
+
+// deleteEntry removes guestbook entry id.
+func (s *store) deleteEntry(id uint64) error {
+	return s.updateGuestbook(func(tx *bolt.Tx) error {
+		return tx.Bucket(guestbookBucket).Delete(entryKey(id))
+	})
+}
+
+// entryKey is the key of guestbook entry id, big-endian so that entries sort
+// in the order they were written.
+func entryKey(id uint64) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, id)
+	return k
+}
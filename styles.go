@@ -1,20 +1,16 @@
 package main
 
-import "github.com/charmbracelet/lipgloss"
-
-// Color constants
-var (
-	accent    = lipgloss.Color("#DC2626")
-	accentDim = lipgloss.Color("#F87171")
-	secondary = lipgloss.Color("#06B6D4")
-	green     = lipgloss.Color("#10B981")
-	pink      = lipgloss.Color("#EC4899")
-	orange    = lipgloss.Color("#F97316")
-	yellow    = lipgloss.Color("#FBBF24")
-	textColor = lipgloss.Color("#E2E8F0")
-	muted     = lipgloss.Color("#94A3B8")
-	dim       = lipgloss.Color("#64748B")
-	subtle    = lipgloss.Color("#334155")
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 // styles holds all lipgloss styles, created from a session-aware renderer.
@@ -31,6 +27,7 @@ type styles struct {
 	pinkText      lipgloss.Style
 	orangeText    lipgloss.Style
 	tag           lipgloss.Style
+	selectedTag   lipgloss.Style
 	activeTab     lipgloss.Style
 	hoverTab      lipgloss.Style
 	inactiveTab   lipgloss.Style
@@ -40,120 +37,127 @@ type styles struct {
 	contentBox    lipgloss.Style
 	divider       lipgloss.Style
 	base          lipgloss.Style // unstyled, for building ad-hoc styles
+	tier          layoutTier     // set by sized from the terminal size
+	theme         Theme
+	g             glyphs
+	l             *locale
 	r             *lipgloss.Renderer
 }
 
-func newStyles(r *lipgloss.Renderer) styles {
-	return styles{
-		r: r,
-		base: r.NewStyle(),
+func newStyles(r *lipgloss.Renderer, t Theme, g glyphs, l *locale) styles {
+	t = t.forProfile(r.ColorProfile())
+	st := styles{
+		r:     r,
+		theme: t,
+		g:     g,
+		l:     l,
+		base:  r.NewStyle(),
 		title: r.NewStyle().
 			Bold(true).
-			Foreground(accent).
+			Foreground(t.Accent).
 			MarginBottom(1),
 		subtitle: r.NewStyle().
-			Foreground(muted).
+			Foreground(t.Muted).
 			Italic(true),
 		sectionHeader: r.NewStyle().
 			Bold(true).
-			Foreground(accent).
+			Foreground(t.Accent).
 			Underline(true).
 			MarginBottom(1),
 		accentText: r.NewStyle().
-			Foreground(accent).
+			Foreground(t.Accent).
 			Bold(true),
 		secondaryText: r.NewStyle().
-			Foreground(secondary).
+			Foreground(t.Secondary).
 			Bold(true),
 		mutedText: r.NewStyle().
-			Foreground(muted),
+			Foreground(t.Muted),
 		dimText: r.NewStyle().
-			Foreground(dim),
+			Foreground(t.Dim),
 		highlightText: r.NewStyle().
-			Foreground(yellow).
+			Foreground(t.Yellow).
 			Bold(true),
 		greenText: r.NewStyle().
-			Foreground(green).
+			Foreground(t.Green).
 			Bold(true),
 		pinkText: r.NewStyle().
-			Foreground(pink).
+			Foreground(t.Pink).
 			Bold(true),
 		orangeText: r.NewStyle().
-			Foreground(orange).
+			Foreground(t.Orange).
 			Bold(true),
 		tag: r.NewStyle().
-			Foreground(secondary).
-			Background(lipgloss.Color("#0E3A4A")).
+			Foreground(t.Secondary).
+			Background(t.TagBackground).
+			Bold(true).
+			Padding(0, 1),
+		selectedTag: r.NewStyle().
+			Foreground(t.OnAccent).
+			Background(t.Accent).
 			Bold(true).
 			Padding(0, 1),
 		activeTab: r.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(accent).
+			Foreground(t.OnAccent).
+			Background(t.Accent).
 			Padding(0, 2),
 		hoverTab: r.NewStyle().
 			Bold(true).
-			Foreground(textColor).
-			Background(subtle).
+			Foreground(t.Text).
+			Background(t.Subtle).
 			Padding(0, 2),
 		inactiveTab: r.NewStyle().
-			Foreground(muted).
+			Foreground(t.Muted).
 			Padding(0, 2),
 		footerKey: r.NewStyle().
-			Foreground(accentDim).
+			Foreground(t.AccentDim).
 			Bold(true),
 		footerDesc: r.NewStyle().
-			Foreground(dim),
+			Foreground(t.Dim),
 		bullet: r.NewStyle().
-			Foreground(green).
+			Foreground(t.Green).
 			Bold(true),
 		contentBox: r.NewStyle().
 			Padding(1, 2),
 		divider: r.NewStyle().
-			Foreground(subtle),
+			Foreground(t.Subtle),
 	}
-}
 
-func (s styles) renderTabBar(tabs []string, active, hover, width int) string {
-	var rendered []string
-	for i, t := range tabs {
-		switch {
-		case i == active:
-			rendered = append(rendered, s.activeTab.Render(t))
-		case i == hover:
-			rendered = append(rendered, s.hoverTab.Render(t))
-		default:
-			rendered = append(rendered, s.inactiveTab.Render(t))
-		}
+	// Without color the active tab and selected tag would look like the rest,
+	// so bracket them instead. The brackets take the place of padding to keep
+	// widths, and so mouse hit-testing, unchanged.
+	if r.ColorProfile() == termenv.Ascii {
+		brackets := func(s string) string { return "[" + s + "]" }
+		st.activeTab = st.activeTab.Padding(0, 1).Transform(brackets)
+		st.selectedTag = st.selectedTag.Padding(0).Transform(brackets)
 	}
-	row := lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
-	bar := s.r.NewStyle().
-		BorderBottom(true).
-		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(accent).
-		Width(width).
-		Render(row)
-	return bar
+	return st
 }
 
-func (s styles) renderFooter(width int) string {
-	parts := []string{
-		s.footerKey.Render("←/→") + s.footerDesc.Render(" navigate"),
-		s.footerKey.Render("↑/↓") + s.footerDesc.Render(" scroll"),
-		s.footerKey.Render("q") + s.footerDesc.Render(" quit"),
-	}
-	sep := s.dimText.Render("  •  ")
-	line := parts[0] + sep + parts[1] + sep + parts[2]
+// footerCopyrightWidth is the footer space reserved for the copyright; the
+// key hints get the rest.
+const footerCopyrightWidth = 24
 
-	copyright := s.dimText.Render("© Daniel Vaughan 2026")
+// renderFooter is the key hints with owner's copyright beside them, or in
+// the compact layout the hints alone on one line.
+func (s styles) renderFooter(hints, owner string, width int) string {
+	if s.tier == tierCompact {
+		return s.r.NewStyle().Width(width).MaxHeight(1).Render(hints)
+	}
+	rightWidth := footerCopyrightWidth
+	year := strconv.Itoa(time.Now().Year())
+	owner = truncate(owner, rightWidth-lipgloss.Width(s.g.Copyright+year)-2, "…")
+	copyright := s.dimText.Render(s.isolate(s.g.Copyright + " " + owner + " " + year))
 
+	// The help view can overrun its width by one item, which would wrap.
 	left := s.r.NewStyle().
-		Width(width / 2).
+		Width(width - rightWidth).
+		MaxHeight(1).
 		Align(lipgloss.Center).
-		Render(line)
+		Render(hints)
 
 	right := s.r.NewStyle().
-		Width(width / 2).
+		Width(rightWidth).
 		Align(lipgloss.Right).
 		Render(copyright)
 
@@ -164,6 +168,31 @@ func (s styles) renderFooter(width int) string {
 		Render(row)
 }
 
+// helpStyles styles the footer hints and help overlay to match the footer.
+func (s styles) helpStyles() help.Styles {
+	return help.Styles{
+		Ellipsis:       s.dimText,
+		ShortKey:       s.footerKey,
+		ShortDesc:      s.footerDesc,
+		ShortSeparator: s.dimText,
+		FullKey:        s.footerKey,
+		FullDesc:       s.mutedText,
+		FullSeparator:  s.dimText,
+	}
+}
+
+// renderHelpOverlay centres the full key binding list in the content area.
+func (s styles) renderHelpOverlay(bindings, closeHint string, width, height int) string {
+	title := s.accentText.Render(s.l.T("Key Bindings"))
+	hint := s.dimText.Render(closeHint)
+	box := s.r.NewStyle().
+		Border(s.g.Border).
+		BorderForeground(s.theme.Accent).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", bindings, "", hint))
+	return s.r.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
 func repeat(s string, n int) string {
 	out := ""
 	for i := 0; i < n; i++ {
@@ -171,3 +200,110 @@ func repeat(s string, n int) string {
 	}
 	return out
 }
+
+// renderThemeMenu lists the themes with a swatch of each one's palette,
+// marking the one under the cursor. It is drawn over the right of content,
+// the content area as it stands, so the theme under the cursor can be seen
+// on the tab; where that leaves too little of the tab, it is centred instead.
+func (s styles) renderThemeMenu(themes []Theme, cursor int, hint, content string, width, height int) string {
+	var rows []string
+	for i, t := range themes {
+		t = t.forProfile(s.r.ColorProfile())
+		var swatch string
+		for _, c := range []lipgloss.Color{t.Accent, t.Secondary, t.Green, t.Pink, t.Orange, t.Yellow, t.Text} {
+			swatch += s.r.NewStyle().Foreground(c).Render(s.g.Block + s.g.Block)
+		}
+		name := s.r.NewStyle().Width(16).Render(t.Name)
+		if i == cursor {
+			rows = append(rows, s.accentText.Render(s.g.Bullet+" ")+s.accentText.Render(name)+swatch)
+		} else {
+			rows = append(rows, "  "+s.mutedText.Render(name)+swatch)
+		}
+	}
+
+	title := s.accentText.Render(s.l.T("Themes"))
+	box := s.r.NewStyle().
+		Border(s.g.Border).
+		BorderForeground(s.theme.Accent).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Left, title, "", lipgloss.JoinVertical(lipgloss.Left, rows...), "", hint))
+	if left := width - lipgloss.Width(box) - 2; left >= 30 && lipgloss.Height(box) <= height {
+		return overlayBox(content, box, left)
+	}
+	return s.r.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
+
+// overlayBox draws box over view, vertically centred, from column x, with
+// the view showing to its left.
+func overlayBox(view, box string, x int) string {
+	lines := strings.Split(view, "\n")
+	boxLines := strings.Split(box, "\n")
+	top := max((len(lines)-len(boxLines))/2, 0)
+	for i, b := range boxLines {
+		if top+i >= len(lines) {
+			break
+		}
+		lines[top+i] = renderCells(fitCells(parseCells(lines[top+i]), x)) + b
+	}
+	return strings.Join(lines, "\n")
+}
+
+// textInput is a one-line text field in the session's styles, width cells
+// wide with its prompt and cursor.
+func (s styles) textInput(placeholder string, limit, width int) textinput.Model {
+	in := textinput.New()
+	in.Prompt = s.g.Bullet + " "
+	in.Placeholder = placeholder
+	in.CharLimit = limit
+	in.Width = width - lipgloss.Width(in.Prompt) - 1
+	in.PromptStyle = s.accentText
+	in.TextStyle = s.r.NewStyle().Foreground(s.theme.Text)
+	in.PlaceholderStyle = s.dimText
+	in.Cursor.Style = s.r.NewStyle().Foreground(s.theme.Accent)
+	in.Cursor.SetMode(cursor.CursorStatic)
+	return in
+}
+
+// textArea is a multi-line text field in the session's styles.
+func (s styles) textArea(placeholder string, limit, width, height int) textarea.Model {
+	in := textarea.New()
+	in.Prompt = s.g.Rail + " "
+	in.Placeholder = placeholder
+	in.CharLimit = limit
+	in.ShowLineNumbers = false
+	in.SetWidth(width)
+	in.SetHeight(height)
+	text := s.r.NewStyle().Foreground(s.theme.Text)
+	in.FocusedStyle = textarea.Style{
+		Base:        s.r.NewStyle(),
+		CursorLine:  text,
+		Text:        text,
+		Placeholder: s.dimText,
+		Prompt:      s.accentText,
+		EndOfBuffer: s.dimText,
+	}
+	in.BlurredStyle = in.FocusedStyle
+	in.BlurredStyle.Prompt = s.dimText
+	in.Cursor.Style = s.r.NewStyle().Foreground(s.theme.Accent)
+	in.Cursor.SetMode(cursor.CursorStatic)
+	return in
+}
+
+// boxFrame is the lines placeBox adds around its content: the border and a
+// line of padding above and below.
+const boxFrame = 4
+
+// placeBox centers a form's content in a bordered box, leaving out the
+// padding above and below if the box would not fit otherwise.
+func (s styles) placeBox(content string, width, height int) string {
+	padding := 1
+	if lipgloss.Height(content)+boxFrame > height {
+		padding = 0
+	}
+	box := s.r.NewStyle().
+		Border(s.g.Border).
+		BorderForeground(s.theme.Accent).
+		Padding(padding, 3).
+		Render(content)
+	return s.r.Place(width, height, lipgloss.Center, lipgloss.Center, box)
+}
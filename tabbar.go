@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// tabSlot is one clickable cell of the rendered tab bar: a tab, or a scroll
+// arrow standing in for the hidden tab it leads to.
+type tabSlot struct {
+	tab     int
+	label   string
+	arrow   bool
+	compact bool
+	x       int
+	width   int
+}
+
+// Labels wider than tabLabelMax cells are cut to tabAbbrevLen when the bar is too
+// narrow for full labels.
+const (
+	tabLabelMax  = 6
+	tabAbbrevLen = 4
+)
+
+// abbreviate shortens a tab label, counting display cells so that wide
+// characters are cut correctly.
+func abbreviate(name string) string {
+	if lipgloss.Width(name) <= tabLabelMax {
+		return name
+	}
+	r := []rune(name)
+	for len(r) > 0 && lipgloss.Width(string(r)) > tabAbbrevLen {
+		r = r[:len(r)-1]
+	}
+	return string(r)
+}
+
+// tabStyle is the style for tab i given the active and hovered tabs. Compact
+// tabs have one cell less padding on each side.
+func (s styles) tabStyle(i, active, hover int, compact bool) lipgloss.Style {
+	st := s.inactiveTab
+	switch {
+	case i == active:
+		st = s.activeTab
+	case i == hover:
+		st = s.hoverTab
+	}
+	if compact {
+		st = st.
+			PaddingLeft(max(st.GetPaddingLeft()-1, 0)).
+			PaddingRight(max(st.GetPaddingRight()-1, 0))
+	}
+	return st
+}
+
+func (s styles) arrowStyle(hover bool) lipgloss.Style {
+	if hover {
+		return s.hoverTab.Padding(0, 1)
+	}
+	return s.dimText.Padding(0, 1)
+}
+
+// layoutTabs places the tabs in width cells: with full labels if they fit,
+// then abbreviated with less padding, and otherwise as a strip scrolled to
+// keep the active tab in view, with arrows leading to the hidden tabs.
+func (s styles) layoutTabs(tabs []string, active, width int) []tabSlot {
+	if s.tier == tierCompact {
+		return s.placeSelector(tabs, active)
+	}
+	if slots := s.placeTabs(tabs, 0, len(tabs), false); slotsWidth(slots) <= width {
+		return slots
+	}
+	if slots := s.placeTabs(tabs, 0, len(tabs), true); slotsWidth(slots) <= width {
+		return slots
+	}
+
+	// Grow a window around the active tab while it fits, arrows included.
+	start, end := active, active+1
+	for grew := true; grew; {
+		grew = false
+		if end < len(tabs) && slotsWidth(s.placeTabs(tabs, start, end+1, true)) <= width {
+			end++
+			grew = true
+		}
+		if start > 0 && slotsWidth(s.placeTabs(tabs, start-1, end, true)) <= width {
+			start--
+			grew = true
+		}
+	}
+	return s.placeTabs(tabs, start, end, true)
+}
+
+// placeTabs lays out tabs[start:end] from the left edge, with an arrow on
+// either side when there are tabs hidden beyond it. Widths are measured from
+// the rendered styles.
+func (s styles) placeTabs(tabs []string, start, end int, compact bool) []tabSlot {
+	var slots []tabSlot
+	x := 0
+	add := func(sl tabSlot, rendered string) {
+		sl.x = x
+		sl.width = lipgloss.Width(rendered)
+		sl.compact = compact
+		x += sl.width
+		slots = append(slots, sl)
+	}
+
+	if start > 0 {
+		add(tabSlot{tab: start - 1, label: s.g.ScrollL, arrow: true}, s.arrowStyle(false).Render(s.g.ScrollL))
+	}
+	for i := start; i < end; i++ {
+		label := tabs[i]
+		if compact {
+			label = abbreviate(label)
+		}
+		add(tabSlot{tab: i, label: label}, s.tabStyle(i, -1, -1, compact).Render(label))
+	}
+	if end < len(tabs) {
+		add(tabSlot{tab: end, label: s.g.ScrollR, arrow: true}, s.arrowStyle(false).Render(s.g.ScrollR))
+	}
+	return slots
+}
+
+// placeSelector is the compact layout's tab bar: just the active tab, with
+// arrows to the tabs either side of it, wrapping around.
+func (s styles) placeSelector(tabs []string, active int) []tabSlot {
+	n := len(tabs)
+	prev, next := (active-1+n)%n, (active+1)%n
+	var slots []tabSlot
+	x := 0
+	for _, sl := range []tabSlot{
+		{tab: prev, label: s.g.ScrollL, arrow: true},
+		{tab: active, label: tabs[active]},
+		{tab: next, label: s.g.ScrollR, arrow: true},
+	} {
+		rendered := s.arrowStyle(false).Render(sl.label)
+		if !sl.arrow {
+			rendered = s.tabStyle(active, active, -1, true).Render(sl.label)
+		}
+		sl.x, sl.width, sl.compact = x, lipgloss.Width(rendered), true
+		x += sl.width
+		slots = append(slots, sl)
+	}
+	return slots
+}
+
+func slotsWidth(slots []tabSlot) int {
+	if len(slots) == 0 {
+		return 0
+	}
+	last := slots[len(slots)-1]
+	return last.x + last.width
+}
+
+// tabAt is the tab under column x of the tab bar, or -1. Clicking an arrow
+// selects the hidden tab next to it.
+func tabAt(slots []tabSlot, x int) int {
+	for _, sl := range slots {
+		if x >= sl.x && x < sl.x+sl.width {
+			return sl.tab
+		}
+	}
+	return -1
+}
+
+func (s styles) renderTabBar(tabs []string, active, hover, width int) string {
+	var rendered []string
+	for _, sl := range s.layoutTabs(tabs, active, width) {
+		if sl.arrow {
+			rendered = append(rendered, s.arrowStyle(sl.tab == hover).Render(sl.label))
+			continue
+		}
+		rendered = append(rendered, s.tabStyle(sl.tab, active, hover, sl.compact).Render(sl.label))
+	}
+	row := lipgloss.JoinHorizontal(lipgloss.Top, rendered...)
+	if s.tier == tierCompact {
+		pos := s.dimText.Render(fmt.Sprintf(" %d/%d", active+1, len(tabs)))
+		return s.r.NewStyle().Width(width).MaxWidth(width).Render(row + pos)
+	}
+	bar := s.r.NewStyle().
+		BorderBottom(true).
+		BorderStyle(s.g.Rule).
+		BorderForeground(s.theme.Accent).
+		Width(width).
+		Render(row)
+	return bar
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Section types say how a tab lays out its content.
+const (
+	sectionBio       = "bio"       // the profile: banner, role and bio
+	sectionTimeline  = "timeline"  // dated entries, newest first
+	sectionCards     = "cards"     // a card per entry, with its tags and link
+	sectionTags      = "tags"      // named groups of tags
+	sectionList      = "list"      // label and value pairs
+	sectionMarkdown  = "markdown"  // a Markdown document
+	sectionPosts     = "posts"     // the Writing tab; not for content files
+	sectionGuestbook = "guestbook" // the Guestbook tab; not for content files
+	sectionAdmin     = "admin"     // the Edit tab; not for content files
+)
+
+// sectionTypes are the types a content file may give a tab.
+var sectionTypes = []string{sectionBio, sectionTimeline, sectionCards, sectionTags, sectionList, sectionMarkdown}
+
+// Tab is one tab of the tab bar. A tab of the timeline, cards, tags or list
+// type shows the portfolio's experiences, projects, skills or contacts
+// unless it has entries, groups or items of its own.
+type Tab struct {
+	Title    string        `json:"-"`               // its key in the content file
+	Order    int           `json:"order,omitempty"` // position in the tab bar, lowest first; 0 goes last
+	Type     string        `json:"type"`
+	Heading  string        `json:"heading,omitempty"`  // section header, the title if unset
+	Name     string        `json:"name,omitempty"`     // bio
+	Role     string        `json:"role,omitempty"`     // bio
+	Location string        `json:"location,omitempty"` // bio
+	Body     string        `json:"body,omitempty"`     // markdown; the bio for bio
+	Entries  []Entry       `json:"entries,omitempty"`  // timeline and cards
+	Groups   []SkillGroup  `json:"groups,omitempty"`   // tags
+	Items    []ContactInfo `json:"items,omitempty"`    // list
+}
+
+// portfolioTabs are the titles of the tabs that hold the portfolio, by type.
+// Content a content file gives them replaces the portfolio's own, and they
+// go on showing it as the portfolio.
+var portfolioTabs = map[string]string{
+	sectionBio:      "About",
+	sectionTimeline: "Experience",
+	sectionCards:    "Projects",
+	sectionTags:     "Skills",
+	sectionList:     "Contact",
+}
+
+// builtin reports whether t shows the portfolio's content for its type: it
+// holds the portfolio, or has no content of its own. Only these tabs have
+// selectable skills and roles.
+func (t Tab) builtin() bool {
+	return portfolioTabs[t.Type] == t.Title || len(t.Entries) == 0 && len(t.Groups) == 0 && len(t.Items) == 0
+}
+
+// profile is the profile bio tab t shows: its own, if it has a name, and
+// the portfolio's otherwise.
+func (t Tab) profile(p portfolio) Profile {
+	if t.Name == "" {
+		return p.profile
+	}
+	return Profile{Name: t.Name, Role: t.Role, Location: t.Location, Bio: t.Body}
+}
+
+func (t Tab) heading() string {
+	if t.Heading != "" {
+		return t.Heading
+	}
+	return t.Title
+}
+
+// cards is the projects a cards tab shows, p's if it is built in.
+func (t Tab) cards(p portfolio) []Project {
+	if t.builtin() {
+		return p.projects
+	}
+	return entryProjects(t.Entries)
+}
+
+// contacts is the contacts a list tab shows, p's if it is built in.
+func (t Tab) contacts(p portfolio) []ContactInfo {
+	if t.builtin() {
+		return p.contacts
+	}
+	return t.Items
+}
+
+func entryProjects(entries []Entry) []Project {
+	out := make([]Project, len(entries))
+	for i, e := range entries {
+		out[i] = Project{Name: e.Title, Description: e.Description, Tech: e.Tags, URL: e.URL}
+	}
+	return out
+}
+
+// portfolioOf is the portfolio tabs show: the built-in one, with each part
+// that a tab in portfolioTabs has content for replaced by that content. The
+// Experience tab's entries are the experiences, with the subtitle as the
+// company, and the About tab's name, role, location and body the profile.
+func portfolioOf(tabs []Tab) portfolio {
+	p := builtinPortfolio
+	for _, t := range tabs {
+		if portfolioTabs[t.Type] != t.Title {
+			continue
+		}
+		switch {
+		case t.Type == sectionBio:
+			p.profile = t.profile(p)
+		case t.Type == sectionTimeline && len(t.Entries) > 0:
+			p.experiences = make([]Experience, len(t.Entries))
+			for i, e := range t.Entries {
+				p.experiences[i] = Experience{Title: e.Title, Company: e.Subtitle, Start: e.Start, End: e.End, Period: e.Period, Description: e.Description, Highlights: e.Highlights}
+			}
+		case t.Type == sectionCards && len(t.Entries) > 0:
+			p.projects = entryProjects(t.Entries)
+		case t.Type == sectionTags && len(t.Groups) > 0:
+			p.skillGroups = t.Groups
+		case t.Type == sectionList && len(t.Items) > 0:
+			p.contacts = t.Items
+		}
+	}
+	return p
+}
+
+// withPortfolio is t with the portfolio content it shows written into it, as
+// a content file would hold it.
+func (t Tab) withPortfolio(p portfolio) Tab {
+	switch {
+	case t.Type == sectionBio && t.Name == "":
+		t.Name, t.Role, t.Location, t.Body = p.profile.Name, p.profile.Role, p.profile.Location, p.profile.Bio
+	case t.Type == sectionBio, len(t.Entries) > 0 || len(t.Groups) > 0 || len(t.Items) > 0:
+		// It has content of its own.
+	case t.Type == sectionTimeline:
+		for _, e := range p.experiences {
+			t.Entries = append(t.Entries, Entry{Title: e.Title, Subtitle: e.Company, Start: e.Start, End: e.End, Period: e.Period, Description: e.Description, Highlights: e.Highlights})
+		}
+	case t.Type == sectionCards:
+		for _, proj := range p.projects {
+			t.Entries = append(t.Entries, Entry{Title: proj.Name, Description: proj.Description, Tags: proj.Tech, URL: proj.URL})
+		}
+	case t.Type == sectionTags:
+		t.Groups = p.skillGroups
+	case t.Type == sectionList:
+		t.Items = p.contacts
+	}
+	return t
+}
+
+// tabTitles is the tab bar's labels, in locale l.
+func tabTitles(tabs []Tab, l *locale) []string {
+	out := make([]string, len(tabs))
+	for i, t := range tabs {
+		out[i] = l.T(t.Title)
+	}
+	return out
+}
+
+// loadTabs reads a JSON file mapping tab titles to tabs and adds them to the
+// built-in tabs, in order. A tab with the same title as a built-in one
+// replaces it.
+func loadTabs(path string) ([]Tab, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTabs(path, data)
+}
+
+// parseTabs is loadTabs for the contents of the file at path.
+func parseTabs(path string, data []byte) ([]Tab, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	titles := make([]string, 0, len(raw))
+	for title := range raw {
+		titles = append(titles, title)
+	}
+	sort.Strings(titles)
+
+	tabs := append([]Tab(nil), builtinTabs...)
+	for _, title := range titles {
+		if title == writingTab || title == guestbookTab || title == editTab {
+			return nil, fmt.Errorf("%s: tab %q: the title is taken by a tab of the app", path, title)
+		}
+		var t Tab
+		dec := json.NewDecoder(bytes.NewReader(raw[title]))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&t); err != nil {
+			return nil, fmt.Errorf("%s: tab %q: %w", path, title, err)
+		}
+		t.Title = title
+		known := false
+		for _, typ := range sectionTypes {
+			known = known || t.Type == typ
+		}
+		if !known {
+			return nil, fmt.Errorf("%s: tab %q: unknown type %q", path, title, t.Type)
+		}
+		if t.Type == sectionBio && t.Name == "" && (t.Role != "" || t.Location != "" || t.Body != "") {
+			return nil, fmt.Errorf("%s: tab %q: a bio needs a name", path, title)
+		}
+
+		replaced := false
+		for i := range tabs {
+			if tabs[i].Title == title {
+				tabs[i], replaced = t, true
+			}
+		}
+		if !replaced {
+			tabs = append(tabs, t)
+		}
+	}
+
+	order := func(t Tab) int {
+		if t.Order == 0 {
+			return math.MaxInt
+		}
+		return t.Order
+	}
+	sort.SliceStable(tabs, func(i, j int) bool { return order(tabs[i]) < order(tabs[j]) })
+	return tabs, nil
+}
+
+// loadLocaleTabs loads the tabs of each locale that has its own content
+// file beside the one at path: tabs.de.json for German beside tabs.json.
+func loadLocaleTabs(path string, locales []*locale) (map[string][]Tab, error) {
+	ext := filepath.Ext(path)
+	out := map[string][]Tab{}
+	for _, l := range locales {
+		p := strings.TrimSuffix(path, ext) + "." + l.Tag + ext
+		if _, err := os.Stat(p); err != nil {
+			continue
+		}
+		tabs, err := loadTabs(p)
+		if err != nil {
+			return nil, err
+		}
+		out[l.Tag] = tabs
+	}
+	return out, nil
+}
+
+// renderEntries renders a timeline tab's entries, newest first.
+func renderEntries(s styles, width int, heading string, entries []Entry) string {
+	var b strings.Builder
+	contentWidth := min(width-4, 72)
+
+	b.WriteString(s.sectionHeader.Render(heading))
+	b.WriteString("\n\n")
+
+	entries = sortedEntries(entries)
+	for i, e := range entries {
+		b.WriteString(s.greenText.Render(s.g.Marker) + "  " + s.accentText.Render(e.Title))
+		b.WriteString("\n")
+		var sub []string
+		if e.Subtitle != "" {
+			sub = append(sub, s.secondaryText.Render(e.Subtitle))
+		}
+		if p := e.PeriodText(); p != "" {
+			sub = append(sub, s.dimText.Render("("+s.l.words(p)+")"))
+		}
+		if len(sub) > 0 {
+			b.WriteString("   " + strings.Join(sub, "  ") + "\n")
+		}
+		if e.Description != "" {
+			desc := s.markdown(e.Description, contentWidth-6, s.r.NewStyle().Foreground(s.theme.Muted))
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, "   ", desc) + "\n")
+		}
+		for _, h := range e.Highlights {
+			text := s.r.NewStyle().
+				Width(contentWidth - 8).
+				Foreground(s.theme.Muted).
+				Render(h)
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, "   ", s.bullet.Render(s.g.Bullet+" "), text) + "\n")
+		}
+
+		if i < len(entries)-1 {
+			b.WriteString("\n")
+		}
+	}
+
+	return b.String()
+}
+
+// renderTagGroups renders a tags tab's groups, each under its name.
+func renderTagGroups(s styles, width int, heading string, groups []SkillGroup) string {
+	var b strings.Builder
+	contentWidth := min(width-4, 72)
+
+	b.WriteString(s.sectionHeader.Render(heading))
+	b.WriteString("\n\n")
+
+	categoryColors := []lipgloss.Color{s.theme.Yellow, s.theme.Green, s.theme.Pink, s.theme.Orange, s.theme.Secondary}
+	for i, group := range groups {
+		color := categoryColors[i%len(categoryColors)]
+		b.WriteString(s.r.NewStyle().Foreground(color).Bold(true).Render(s.g.Square + " " + group.Category))
+		b.WriteString("\n")
+
+		var tags []string
+		for _, t := range group.Skills {
+			tags = append(tags, s.tag.Render(s.isolate(t)))
+		}
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, "  ", s.r.NewStyle().Width(contentWidth-2).Render(strings.Join(tags, " "))))
+		b.WriteString("\n")
+
+		if i < len(groups)-1 {
+			b.WriteString(s.dimText.Render("  "+repeat(s.g.Dot, contentWidth-4)) + "\n")
+		}
+	}
+
+	return b.String()
+}
+
+// renderList renders a list tab's items as aligned labels and values, with
+// values that are addresses linked and clickable to copy.
+func renderList(s styles, width int, heading string, items []ContactInfo) string {
+	var b strings.Builder
+
+	b.WriteString(s.sectionHeader.Render(heading))
+	b.WriteString("\n\n")
+
+	labelWidth := 0
+	for _, c := range items {
+		labelWidth = max(labelWidth, lipgloss.Width(s.l.T(c.Label)))
+	}
+	valueWidth := max(min(width-4, 72)-labelWidth-4, 10)
+	for i, c := range items {
+		label := s.secondaryText.Bold(true).Width(labelWidth).Render(s.l.T(c.Label))
+		value := s.isolate(c.Value)
+		if u := urlFor(c.Label, c.Value); u != "" {
+			value = hyperlink(u, value)
+		}
+		styled := s.r.NewStyle().Foreground(s.theme.Text).Width(valueWidth).Render(value)
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, "  ", label, "  ", markZone(zoneContact, i, styled)))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderMarkdownTab renders a markdown tab's body.
+func renderMarkdownTab(s styles, width int, heading, body string) string {
+	return s.sectionHeader.Render(heading) + "\n\n" +
+		s.markdown(body, min(width-4, 72), s.r.NewStyle().Foreground(s.theme.Text)) + "\n"
+}
+
+// plainEntries reads out a timeline tab's entries.
+func plainEntries(l *locale, entries []Entry) string {
+	var b strings.Builder
+	entries = sortedEntries(entries)
+	for i, e := range entries {
+		b.WriteString(l.F("Entry %d of %d: %s", i+1, len(entries), e.Title) + "\n")
+		if e.Subtitle != "" {
+			b.WriteString(e.Subtitle + "\n")
+		}
+		if p := e.PeriodText(); p != "" {
+			b.WriteString(l.F("Dates: %s", plainPeriod(l, p)) + "\n")
+		}
+		if e.Description != "" {
+			b.WriteString(plainMarkdown(e.Description) + "\n")
+		}
+		if len(e.Highlights) > 0 {
+			b.WriteString(l.F("Highlights, %s:", items(l, len(e.Highlights))) + "\n")
+			for _, h := range e.Highlights {
+				b.WriteString("- " + h + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
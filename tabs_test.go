@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestBioTabProfile(t *testing.T) {
+	data := []byte(`{"Team": {"type": "bio", "name": "Grace Hopper", "role": "Admiral", "body": "Wrote a compiler."}, "Blank": {"type": "bio"}}`)
+	tabs, err := parseTabs("tabs.json", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := portfolioOf(tabs)
+	for _, tab := range tabs {
+		got := tab.profile(p)
+		switch tab.Title {
+		case "Team":
+			if got.Name != "Grace Hopper" || got.Role != "Admiral" || got.Bio != "Wrote a compiler." {
+				t.Errorf("Team shows %+v, want its own profile", got)
+			}
+		case "Blank", "About":
+			if got != profile {
+				t.Errorf("%s shows %+v, want the portfolio's", tab.Title, got)
+			}
+		}
+	}
+
+	targets, err := editTargets(data, tabs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if team := findTarget(t, targets, "Team", "Tab settings"); team.values[0] != "Grace Hopper" {
+		t.Errorf("Team lists name %q", team.values[0])
+	}
+
+	if _, err := parseTabs("tabs.json", []byte(`{"Team": {"type": "bio", "body": "No name."}}`)); err == nil {
+		t.Error("a bio without a name loaded")
+	}
+}
@@ -0,0 +1,499 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Palette is the set of colors a theme paints with.
+type Palette struct {
+	Accent        lipgloss.Color   `json:"accent,omitempty"`
+	AccentDim     lipgloss.Color   `json:"accent_dim,omitempty"`
+	Secondary     lipgloss.Color   `json:"secondary,omitempty"`
+	Green         lipgloss.Color   `json:"green,omitempty"`
+	Pink          lipgloss.Color   `json:"pink,omitempty"`
+	Orange        lipgloss.Color   `json:"orange,omitempty"`
+	Yellow        lipgloss.Color   `json:"yellow,omitempty"`
+	Text          lipgloss.Color   `json:"text,omitempty"`
+	Muted         lipgloss.Color   `json:"muted,omitempty"`
+	Dim           lipgloss.Color   `json:"dim,omitempty"`
+	Subtle        lipgloss.Color   `json:"subtle,omitempty"`
+	TagBackground lipgloss.Color   `json:"tag_background,omitempty"`
+	OnAccent      lipgloss.Color   `json:"on_accent,omitempty"` // text on accent backgrounds
+	Banner        []lipgloss.Color `json:"banner,omitempty"`    // banner gradient, top to bottom
+}
+
+// Theme is a named palette. newStyles builds every style from one, and the
+// renderers in views.go take their colors from it rather than hard-coding
+// them.
+//
+// The embedded palette is in truecolor. ANSI256 and ANSI optionally override
+// some or all of it with colors hand-picked for 256- and 16-color terminals;
+// anything they leave unset falls back to lipgloss' nearest match.
+type Theme struct {
+	Name string `json:"-"`
+	Palette
+	ANSI256 *Palette `json:"ansi256,omitempty"`
+	ANSI    *Palette `json:"ansi,omitempty"`
+}
+
+// forProfile returns the theme with its palette swapped for the one tuned
+// to the color profile.
+func (t Theme) forProfile(p termenv.Profile) Theme {
+	switch {
+	case p == termenv.ANSI256 && t.ANSI256 != nil:
+		t.Palette = t.Palette.with(*t.ANSI256)
+	case p == termenv.ANSI && t.ANSI != nil:
+		t.Palette = t.Palette.with(*t.ANSI)
+	}
+	return t
+}
+
+// with returns p with every color set in o replacing its own.
+func (p Palette) with(o Palette) Palette {
+	pick := func(dst *lipgloss.Color, src lipgloss.Color) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	pick(&p.Accent, o.Accent)
+	pick(&p.AccentDim, o.AccentDim)
+	pick(&p.Secondary, o.Secondary)
+	pick(&p.Green, o.Green)
+	pick(&p.Pink, o.Pink)
+	pick(&p.Orange, o.Orange)
+	pick(&p.Yellow, o.Yellow)
+	pick(&p.Text, o.Text)
+	pick(&p.Muted, o.Muted)
+	pick(&p.Dim, o.Dim)
+	pick(&p.Subtle, o.Subtle)
+	pick(&p.TagBackground, o.TagBackground)
+	pick(&p.OnAccent, o.OnAccent)
+	if len(o.Banner) > 0 {
+		p.Banner = o.Banner
+	}
+	return p
+}
+
+// colors is pointers to each of p's colors, less the banner.
+func (p *Palette) colors() []*lipgloss.Color {
+	return []*lipgloss.Color{
+		&p.Accent, &p.AccentDim, &p.Secondary, &p.Green, &p.Pink, &p.Orange, &p.Yellow,
+		&p.Text, &p.Muted, &p.Dim, &p.Subtle, &p.TagBackground, &p.OnAccent,
+	}
+}
+
+// without returns p with the colors set in o unset, except those keep sets
+// too.
+func (p Palette) without(o, keep Palette) Palette {
+	mine, theirs, kept := p.colors(), o.colors(), keep.colors()
+	for i := range mine {
+		if *theirs[i] != "" && *kept[i] == "" {
+			*mine[i] = ""
+		}
+	}
+	if len(o.Banner) > 0 && len(keep.Banner) == 0 {
+		p.Banner = nil
+	}
+	return p
+}
+
+// bannerColor is the color of line i of an n-line banner: the banner stops
+// spread evenly from the top line to the bottom one, blended in between when
+// both neighbouring stops are hex colors.
+func bannerColor(stops []lipgloss.Color, i, n int) lipgloss.Color {
+	if len(stops) == 1 || n < 2 {
+		return stops[0]
+	}
+	pos := float64(i) * float64(len(stops)-1) / float64(n-1)
+	lo := int(pos)
+	hi := min(lo+1, len(stops)-1)
+	var a, b [3]int
+	_, errA := fmt.Sscanf(string(stops[lo]), "#%02x%02x%02x", &a[0], &a[1], &a[2])
+	_, errB := fmt.Sscanf(string(stops[hi]), "#%02x%02x%02x", &b[0], &b[1], &b[2])
+	if errA != nil || errB != nil {
+		return stops[int(math.Round(pos))]
+	}
+	t := pos - float64(lo)
+	var c [3]int
+	for k := range c {
+		c[k] = int(math.Round(float64(a[k]) + t*float64(b[k]-a[k])))
+	}
+	return lipgloss.Color(fmt.Sprintf("#%02X%02X%02X", c[0], c[1], c[2]))
+}
+
+var darkTheme = Theme{
+	Name: "dark",
+	Palette: Palette{
+		Accent:        "#DC2626",
+		AccentDim:     "#F87171",
+		Secondary:     "#06B6D4",
+		Green:         "#10B981",
+		Pink:          "#EC4899",
+		Orange:        "#F97316",
+		Yellow:        "#FBBF24",
+		Text:          "#E2E8F0",
+		Muted:         "#94A3B8",
+		Dim:           "#64748B",
+		Subtle:        "#334155",
+		TagBackground: "#0E3A4A",
+		OnAccent:      "#FFFFFF",
+		Banner:        []lipgloss.Color{"#F87171", "#DC2626", "#B91C1C", "#DC2626", "#F87171", "#FCA5A5"},
+	},
+	ANSI256: &Palette{
+		Accent:        "160",
+		AccentDim:     "210",
+		Secondary:     "44",
+		Green:         "36",
+		Pink:          "205",
+		Orange:        "208",
+		Yellow:        "214",
+		Text:          "254",
+		Muted:         "248",
+		Dim:           "244",
+		Subtle:        "238",
+		TagBackground: "23",
+		OnAccent:      "231",
+		Banner:        []lipgloss.Color{"210", "160", "124", "160", "210", "217"},
+	},
+	ANSI: &Palette{
+		Accent:        "1",
+		AccentDim:     "9",
+		Secondary:     "14",
+		Green:         "2",
+		Pink:          "5",
+		Orange:        "3",
+		Yellow:        "11",
+		Text:          "15",
+		Muted:         "7",
+		Dim:           "8",
+		Subtle:        "8",
+		TagBackground: "0",
+		OnAccent:      "15",
+		Banner:        []lipgloss.Color{"9", "1", "1", "1", "9", "9"},
+	},
+}
+
+var lightTheme = Theme{
+	Name: "light",
+	Palette: Palette{
+		Accent:        "#B91C1C",
+		AccentDim:     "#DC2626",
+		Secondary:     "#0E7490",
+		Green:         "#047857",
+		Pink:          "#BE185D",
+		Orange:        "#C2410C",
+		Yellow:        "#A16207",
+		Text:          "#1E293B",
+		Muted:         "#475569",
+		Dim:           "#64748B",
+		Subtle:        "#CBD5E1",
+		TagBackground: "#CFFAFE",
+		OnAccent:      "#FFFFFF",
+		Banner:        []lipgloss.Color{"#DC2626", "#B91C1C", "#991B1B", "#B91C1C", "#DC2626", "#EF4444"},
+	},
+	ANSI256: &Palette{
+		Accent:        "124",
+		AccentDim:     "160",
+		Secondary:     "30",
+		Green:         "29",
+		Pink:          "162",
+		Orange:        "166",
+		Yellow:        "136",
+		Text:          "235",
+		Muted:         "240",
+		Dim:           "244",
+		Subtle:        "252",
+		TagBackground: "195",
+		OnAccent:      "231",
+		Banner:        []lipgloss.Color{"160", "124", "88", "124", "160", "203"},
+	},
+	ANSI: &Palette{
+		Accent:        "1",
+		AccentDim:     "1",
+		Secondary:     "4",
+		Green:         "2",
+		Pink:          "5",
+		Orange:        "3",
+		Yellow:        "3",
+		Text:          "0",
+		Muted:         "8",
+		Dim:           "8",
+		Subtle:        "7",
+		TagBackground: "7",
+		OnAccent:      "15",
+		Banner:        []lipgloss.Color{"1"},
+	},
+}
+
+// solarizedTheme is Solarized dark. Its 16-color palette uses the standard
+// Solarized terminal slot assignments, so it is exact on terminals that have
+// the Solarized palette loaded.
+var solarizedTheme = Theme{
+	Name: "solarized",
+	Palette: Palette{
+		Accent:        "#DC322F",
+		AccentDim:     "#CB4B16",
+		Secondary:     "#2AA198",
+		Green:         "#859900",
+		Pink:          "#D33682",
+		Orange:        "#CB4B16",
+		Yellow:        "#B58900",
+		Text:          "#93A1A1",
+		Muted:         "#839496",
+		Dim:           "#657B83",
+		Subtle:        "#073642",
+		TagBackground: "#073642",
+		OnAccent:      "#FDF6E3",
+		Banner:        []lipgloss.Color{"#B58900", "#CB4B16", "#DC322F", "#D33682", "#6C71C4", "#268BD2"},
+	},
+	ANSI256: &Palette{
+		Accent:        "160",
+		AccentDim:     "166",
+		Secondary:     "37",
+		Green:         "64",
+		Pink:          "125",
+		Orange:        "166",
+		Yellow:        "136",
+		Text:          "245",
+		Muted:         "244",
+		Dim:           "241",
+		Subtle:        "235",
+		TagBackground: "235",
+		OnAccent:      "230",
+		Banner:        []lipgloss.Color{"136", "166", "160", "125", "61", "33"},
+	},
+	ANSI: &Palette{
+		Accent:        "1",
+		AccentDim:     "9",
+		Secondary:     "6",
+		Green:         "2",
+		Pink:          "5",
+		Orange:        "9",
+		Yellow:        "3",
+		Text:          "14",
+		Muted:         "12",
+		Dim:           "10",
+		Subtle:        "0",
+		TagBackground: "0",
+		OnAccent:      "15",
+		Banner:        []lipgloss.Color{"3", "9", "1", "5", "13", "4"},
+	},
+}
+
+var highContrastTheme = Theme{
+	Name: "high-contrast",
+	Palette: Palette{
+		Accent:        "#FF5F5F",
+		AccentDim:     "#FF8787",
+		Secondary:     "#00FFFF",
+		Green:         "#00FF00",
+		Pink:          "#FF00FF",
+		Orange:        "#FFAF00",
+		Yellow:        "#FFFF00",
+		Text:          "#FFFFFF",
+		Muted:         "#FFFFFF",
+		Dim:           "#D0D0D0",
+		Subtle:        "#808080",
+		TagBackground: "#000000",
+		OnAccent:      "#000000",
+		Banner:        []lipgloss.Color{"#FFFF00"},
+	},
+	ANSI256: &Palette{
+		Accent:        "203",
+		AccentDim:     "210",
+		Secondary:     "51",
+		Green:         "46",
+		Pink:          "201",
+		Orange:        "214",
+		Yellow:        "226",
+		Text:          "231",
+		Muted:         "231",
+		Dim:           "252",
+		Subtle:        "244",
+		TagBackground: "16",
+		OnAccent:      "16",
+		Banner:        []lipgloss.Color{"226"},
+	},
+	ANSI: &Palette{
+		Accent:        "9",
+		AccentDim:     "9",
+		Secondary:     "14",
+		Green:         "10",
+		Pink:          "13",
+		Orange:        "11",
+		Yellow:        "11",
+		Text:          "15",
+		Muted:         "15",
+		Dim:           "7",
+		Subtle:        "8",
+		TagBackground: "0",
+		OnAccent:      "0",
+		Banner:        []lipgloss.Color{"11"},
+	},
+}
+
+var monochromeTheme = Theme{
+	Name: "monochrome",
+	Palette: Palette{
+		Accent:        "#FFFFFF",
+		AccentDim:     "#D0D0D0",
+		Secondary:     "#E0E0E0",
+		Green:         "#C0C0C0",
+		Pink:          "#C0C0C0",
+		Orange:        "#C0C0C0",
+		Yellow:        "#FFFFFF",
+		Text:          "#D0D0D0",
+		Muted:         "#A0A0A0",
+		Dim:           "#707070",
+		Subtle:        "#404040",
+		TagBackground: "#303030",
+		OnAccent:      "#000000",
+		Banner:        []lipgloss.Color{"#FFFFFF", "#D0D0D0", "#A0A0A0", "#D0D0D0", "#FFFFFF", "#E0E0E0"},
+	},
+	ANSI256: &Palette{
+		Accent:        "231",
+		AccentDim:     "252",
+		Secondary:     "254",
+		Green:         "250",
+		Pink:          "250",
+		Orange:        "250",
+		Yellow:        "231",
+		Text:          "252",
+		Muted:         "247",
+		Dim:           "242",
+		Subtle:        "238",
+		TagBackground: "236",
+		OnAccent:      "16",
+		Banner:        []lipgloss.Color{"231", "252", "247", "252", "231", "254"},
+	},
+	ANSI: &Palette{
+		Accent:        "15",
+		AccentDim:     "7",
+		Secondary:     "15",
+		Green:         "7",
+		Pink:          "7",
+		Orange:        "7",
+		Yellow:        "15",
+		Text:          "7",
+		Muted:         "7",
+		Dim:           "8",
+		Subtle:        "8",
+		TagBackground: "8",
+		OnAccent:      "0",
+		Banner:        []lipgloss.Color{"15", "7"},
+	},
+}
+
+// builtinThemes are the themes that ship with the server.
+var builtinThemes = []Theme{darkTheme, lightTheme, solarizedTheme, highContrastTheme, monochromeTheme}
+
+// findTheme looks a theme up by name.
+func findTheme(themes []Theme, name string) (Theme, bool) {
+	for _, t := range themes {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// autoTheme picks the dark or light theme to suit the terminal background.
+func autoTheme(r *lipgloss.Renderer) Theme {
+	if r.HasDarkBackground() {
+		return darkTheme
+	}
+	return lightTheme
+}
+
+// loadThemes reads custom themes from a JSON file mapping theme names to
+// palettes. A palette may name a "base" theme to start from (dark by default)
+// and override only some of its colors:
+//
+//	{"dracula": {"base": "dark", "accent": "#FF79C6", "secondary": "#8BE9FD"}}
+//
+// The custom themes are returned after the built-in ones, replacing any
+// built-in theme of the same name.
+func loadThemes(path string) ([]Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	names := make([]string, 0, len(raw))
+	for name := range raw {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	themes := append([]Theme(nil), builtinThemes...)
+	for _, name := range names {
+		var hdr struct {
+			Base string `json:"base"`
+		}
+		if err := json.Unmarshal(raw[name], &hdr); err != nil {
+			return nil, fmt.Errorf("%s: theme %q: %w", path, name, err)
+		}
+		if hdr.Base == "" {
+			hdr.Base = darkTheme.Name
+		}
+		t, ok := findTheme(builtinThemes, hdr.Base)
+		if !ok {
+			return nil, fmt.Errorf("%s: theme %q: unknown base theme %q", path, name, hdr.Base)
+		}
+		t.Banner = append([]lipgloss.Color(nil), t.Banner...)
+		if t.ANSI256 != nil {
+			p := *t.ANSI256
+			t.ANSI256 = &p
+		}
+		if t.ANSI != nil {
+			p := *t.ANSI
+			t.ANSI = &p
+		}
+		var own Theme
+		if err := json.Unmarshal(raw[name], &own); err != nil {
+			return nil, fmt.Errorf("%s: theme %q: %w", path, name, err)
+		}
+		if err := json.Unmarshal(raw[name], &t); err != nil {
+			return nil, fmt.Errorf("%s: theme %q: %w", path, name, err)
+		}
+		// The base's hand-picked 256- and 16-color palettes only stand for
+		// its own truecolor colors: where the theme sets another, the nearest
+		// match to it is used unless the theme picks one itself.
+		for _, p := range []struct{ inherited, set *Palette }{{t.ANSI256, own.ANSI256}, {t.ANSI, own.ANSI}} {
+			if p.inherited == nil {
+				continue
+			}
+			keep := Palette{}
+			if p.set != nil {
+				keep = *p.set
+			}
+			*p.inherited = p.inherited.without(own.Palette, keep)
+		}
+		if len(t.Banner) == 0 {
+			t.Banner = []lipgloss.Color{t.Accent}
+		}
+		t.Name = name
+
+		replaced := false
+		for i := range themes {
+			if themes[i].Name == name {
+				themes[i], replaced = t, true
+			}
+		}
+		if !replaced {
+			themes = append(themes, t)
+		}
+	}
+	return themes, nil
+}
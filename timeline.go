@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderTimeline draws experiences as a horizontal Gantt chart with years on
+// the axis. Each role gets its own row, ordered by start date, so overlapping
+// roles are stacked and their overlap is visible at a glance.
+func renderTimeline(s styles, experiences []Experience, width int) string {
+	var b strings.Builder
+	contentWidth := min(width-4, 72)
+
+	b.WriteString(s.sectionHeader.Render(s.l.T("Career Timeline")))
+	b.WriteString("\n\n")
+
+	var rows []Experience
+	for _, exp := range experiences {
+		if !exp.Start.IsZero() && !exp.End.IsZero() {
+			rows = append(rows, exp)
+		}
+	}
+	if len(rows) == 0 {
+		b.WriteString(s.dimText.Render(s.l.T("No dated experience to show.")))
+		b.WriteString("\n")
+		return b.String()
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].Start.Before(rows[j].Start)
+	})
+
+	first, last := rows[0].Start.Year, rows[0].End.Time().Year()
+	for _, r := range rows {
+		last = max(last, r.End.Time().Year())
+	}
+	origin := time.Date(first, time.January, 1, 0, 0, 0, 0, time.UTC)
+	span := (last - first + 1) * 12
+
+	labelWidth := min(20, contentWidth/3)
+	chartWidth := max(contentWidth-labelWidth-2, 1)
+	col := func(t time.Time) int {
+		return monthsBetween(origin, t) * chartWidth / span
+	}
+
+	yearCols := map[int]bool{}
+	for y := first; y <= last; y++ {
+		yearCols[col(time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC))] = true
+	}
+
+	barColors := []lipgloss.Color{s.theme.Accent, s.theme.Secondary, s.theme.Green, s.theme.Pink, s.theme.Orange, s.theme.Yellow}
+	labelStyle := s.r.NewStyle().Width(labelWidth).MaxWidth(labelWidth)
+
+	for i, r := range rows {
+		from := col(r.Start.Time())
+		to := max(col(r.End.Time())+1, from+1)
+		bar := s.r.NewStyle().Foreground(barColors[i%len(barColors)])
+
+		var line strings.Builder
+		for c := 0; c < chartWidth; c++ {
+			switch {
+			case c >= from && c < to:
+				line.WriteString(bar.Render(s.g.Block))
+			case yearCols[c]:
+				line.WriteString(s.divider.Render(s.g.Grid))
+			default:
+				line.WriteString(" ")
+			}
+		}
+		b.WriteString(labelStyle.Render(s.secondaryText.Render(truncate(r.Company, labelWidth-1, s.g.Ellipsis))))
+		b.WriteString("  " + line.String() + "\n")
+	}
+
+	// Axis with a tick at each January and the year printed beneath it when
+	// there is room.
+	var axis, years strings.Builder
+	next := 0
+	for c := 0; c < chartWidth; c++ {
+		if yearCols[c] {
+			axis.WriteString(s.g.Tick)
+		} else {
+			axis.WriteString(s.g.Axis)
+		}
+	}
+	for y := first; y <= last; y++ {
+		c := col(time.Date(y, time.January, 1, 0, 0, 0, 0, time.UTC))
+		label := fmt.Sprint(y)
+		if c < next || c+lipgloss.Width(label) > chartWidth {
+			continue
+		}
+		years.WriteString(strings.Repeat(" ", c-next) + label)
+		next = c + lipgloss.Width(label) + 1
+		years.WriteString(" ")
+	}
+	pad := strings.Repeat(" ", labelWidth+2)
+	b.WriteString(pad + s.dimText.Render(axis.String()) + "\n")
+	b.WriteString(pad + s.mutedText.Render(strings.TrimRight(years.String(), " ")) + "\n\n")
+
+	for i, r := range rows {
+		swatch := s.r.NewStyle().Foreground(barColors[i%len(barColors)]).Render(s.g.Block)
+		b.WriteString(swatch + " " + s.accentText.Render(r.Title) + "\n")
+		b.WriteString("  " + s.secondaryText.Render(r.Company) + "  " + s.dimText.Render(s.l.words(r.PeriodText())))
+		if t := r.Tenure(); t != "" {
+			b.WriteString(s.dimText.Render("  " + s.g.Dot + " " + s.l.words(t)))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// truncate shortens s to at most n cells, marking the cut with ellipsis.
+func truncate(s string, n int, ellipsis string) string {
+	if lipgloss.Width(s) <= n {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && lipgloss.Width(string(r))+lipgloss.Width(ellipsis) > n {
+		r = r[:len(r)-1]
+	}
+	return string(r) + ellipsis
+}
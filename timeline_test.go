@@ -0,0 +1,28 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestTimelineFitsNarrowWidths(t *testing.T) {
+	s := newStyles(lipgloss.NewRenderer(io.Discard), builtinThemes[0], unicodeGlyphs, english)
+	for width := minWidth; width <= 40; width++ {
+		contentWidth := min(width-4, 72)
+		// The chart is between the header and the legend, which wraps like
+		// any other text, with a blank line either side.
+		_, chart, _ := strings.Cut(renderTimeline(s, experiences, width), "\n\n")
+		chart, _, _ = strings.Cut(chart, "\n\n")
+		if chart == "" {
+			t.Fatalf("width %d: no chart", width)
+		}
+		for _, line := range strings.Split(chart, "\n") {
+			if w := lipgloss.Width(line); w > contentWidth {
+				t.Errorf("width %d: chart line is %d cells, more than %d: %q", width, w, contentWidth, line)
+			}
+		}
+	}
+}
@@ -8,19 +8,6 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-var asciiLines = []string{
-	` ____              _      _  __     __                 _`,
-	`|  _ \  __ _ _ __ (_) ___| | \ \   / /_ _ _   _  __ _| |__   __ _ _ __`,
-	"| | | |/ _` | '_ \\| |/ _ \\ |  \\ \\ / / _` | | | |/ _` | '_ \\ / _` | '_ \\",
-	`| |_| | (_| | | | | |  __/ |   \ V / (_| | |_| | (_| | | | | (_| | | | |`,
-	`|____/ \__,_|_| |_|_|\___|_|    \_/ \__,_|\__,_|\__, |_| |_|\__,_|_| |_|`,
-	`                                                 |___/`,
-}
-
-var bannerColors = []lipgloss.Color{
-	"#F87171", "#DC2626", "#B91C1C", "#DC2626", "#F87171", "#FCA5A5",
-}
-
 // hyperlink wraps text in an OSC 8 clickable hyperlink escape sequence.
 // Terminals that don't support it will just show the display text.
 func hyperlink(url, text string) string {
@@ -43,30 +30,61 @@ func urlFor(label, value string) string {
 	}
 }
 
-func renderAbout(s styles, width int) string {
+// webURL is the https URL for a contact, or "" if it is not a web address.
+func (c ContactInfo) webURL() string {
+	if c.Label == "Portfolio" {
+		return "https://" + c.Value
+	}
+	if u := urlFor(c.Label, c.Value); strings.HasPrefix(u, "https://") {
+		return u
+	}
+	return ""
+}
+
+// link is the project's full URL, or "" if it has none.
+func (p Project) link() string {
+	if p.URL == "" || strings.Contains(p.URL, "://") {
+		return p.URL
+	}
+	return "https://" + p.URL
+}
+
+// renderAbout renders the About tab, with the profile name set as a banner
+// in the first of fonts that fits.
+func renderAbout(s styles, profile Profile, width int, fonts []*figFont) string {
 	var b strings.Builder
 
-	for i, line := range asciiLines {
-		color := bannerColors[i%len(bannerColors)]
-		b.WriteString(s.r.NewStyle().Foreground(color).Bold(true).Render(line))
+	// The banner shrinks or wraps to fit, and is left out where nothing fits.
+	var lines []string
+	if s.tier != tierCompact {
+		lines = banner(fonts, profile.Name, width-4)
+	}
+	if len(lines) > 0 {
+		// Lines are padded to one width to stay aligned when mirrored.
+		bannerWidth := linesWidth(lines)
+		for i, line := range lines {
+			color := bannerColor(s.theme.Banner, i, len(lines))
+			line = s.ltr(line + strings.Repeat(" ", bannerWidth-len([]rune(line))))
+			b.WriteString(s.r.NewStyle().Foreground(color).Bold(true).Render(line))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	} else {
+		b.WriteString(s.title.Render(profile.Name))
 		b.WriteString("\n")
 	}
-	b.WriteString("\n")
 
 	role := s.greenText.Render(profile.Role)
-	sep := s.dimText.Render("  ◆  ")
+	sep := s.dimText.Render("  " + s.g.Separator + "  ")
 	loc := s.secondaryText.Render(profile.Location)
-	b.WriteString(role + sep + loc)
+	b.WriteString(s.r.NewStyle().Width(width - 4).Render(role + sep + loc))
 	b.WriteString("\n\n")
 
 	bioWidth := min(width-8, 70)
-	bio := s.r.NewStyle().
-		Width(bioWidth).
-		Foreground(textColor).
-		Render(profile.Bio)
+	bio := s.markdown(profile.Bio, bioWidth, s.r.NewStyle().Foreground(s.theme.Text))
 	box := s.r.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(subtle).
+		Border(s.g.Border).
+		BorderForeground(s.theme.Subtle).
 		Padding(1, 2).
 		Render(bio)
 	b.WriteString(box)
@@ -75,203 +93,268 @@ func renderAbout(s styles, width int) string {
 	return b.String()
 }
 
-func renderExperience(s styles, width int) string {
+func renderExperience(s styles, p portfolio, width, selected int) string {
+	if s.tier == tierWide {
+		return renderExperienceWide(s, p, width, selected)
+	}
+
 	var b strings.Builder
 	contentWidth := min(width-4, 72)
 
-	b.WriteString(s.sectionHeader.Render("Work Experience"))
+	b.WriteString(s.sectionHeader.Render(s.l.T("Work Experience")))
 	b.WriteString("\n\n")
 
-	for i, exp := range experiences {
-		marker := s.greenText.Render("●")
-		line := s.dimText.Render("│")
+	exps := p.sortedExperiences()
+	for i, exp := range exps {
+		marker := s.greenText.Render(s.g.Marker)
+		line := s.dimText.Render(s.g.Rail)
 
 		period := s.r.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
-			Background(lipgloss.Color("#334155")).
+			Foreground(s.theme.Text).
+			Background(s.theme.Subtle).
 			Bold(true).
 			Padding(0, 1).
-			Render(exp.Period)
+			Render(s.l.words(exp.PeriodText()))
+		if t := exp.Tenure(); t != "" {
+			period += s.dimText.Render("  " + s.l.words(t))
+		}
 
 		b.WriteString(marker + "  " + s.accentText.Render(exp.Title) + "\n")
 		b.WriteString(line + "  " + s.secondaryText.Render(exp.Company) + "  " + period + "\n")
 		b.WriteString(line + "\n")
-		b.WriteString(line + "  " + s.r.NewStyle().
-			Width(contentWidth-6).
-			Foreground(textColor).
-			Italic(true).
-			Render(exp.Description))
+		b.WriteString(line + "  " + s.markdown(exp.Description, contentWidth-6, s.r.NewStyle().Foreground(s.theme.Text).Italic(true)))
 		b.WriteString("\n")
 
 		for _, h := range exp.Highlights {
-			b.WriteString(line + "  " + s.bullet.Render("▸ "))
+			b.WriteString(line + "  " + s.bullet.Render(s.g.Bullet+" "))
 			b.WriteString(s.r.NewStyle().
-				Width(contentWidth-8).
-				Foreground(muted).
+				Width(contentWidth - 8).
+				Foreground(s.theme.Muted).
 				Render(h))
 			b.WriteString("\n")
 		}
 
-		if i < len(experiences)-1 {
+		if i < len(exps)-1 {
 			b.WriteString(line + "\n")
 		} else {
-			b.WriteString(s.dimText.Render("╵") + "\n")
+			b.WriteString(s.dimText.Render(s.g.RailEnd) + "\n")
 		}
 	}
 
 	return b.String()
 }
 
-func renderProjects(s styles, width int) string {
+// renderProjects renders projects as cards under heading, with the tags that
+// are p's skills linked to them.
+func renderProjects(s styles, p portfolio, width int, heading string, projects []Project) string {
+	if s.tier == tierWide {
+		return renderProjectGrid(s, p, width, heading, projects)
+	}
+
 	var b strings.Builder
 	cardWidth := min(width-8, 68)
 
-	b.WriteString(s.sectionHeader.Render("Projects"))
+	b.WriteString(s.sectionHeader.Render(heading))
 	b.WriteString("\n\n")
 
-	for _, proj := range projects {
-		name := s.accentText.Render("◈  " + proj.Name)
-
-		desc := s.r.NewStyle().
-			Width(cardWidth - 4).
-			Foreground(textColor).
-			Render(proj.Description)
-
-		var tags []string
-		for _, t := range proj.Tech {
-			tags = append(tags, s.tag.Render(t))
-		}
-		tagLine := strings.Join(tags, " ")
+	for i, proj := range projects {
+		b.WriteString(renderProjectCard(s, p, i, proj, cardWidth, 0))
+		b.WriteString("\n")
+	}
 
-		url := s.dimText.Render("→ ") + s.secondaryText.Render(hyperlink("https://"+proj.URL, proj.URL))
+	return b.String()
+}
 
-		inner := lipgloss.JoinVertical(lipgloss.Left, name, "", desc, "", tagLine, url)
+// renderProjectCard renders project i as a card cardWidth wide and, if height
+// is set, that many lines tall, with the tags that are p's skills linked.
+func renderProjectCard(s styles, p portfolio, i int, proj Project, cardWidth, height int) string {
+	name := s.accentText.Render(s.g.Card + "  " + proj.Name)
 
-		card := s.r.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(subtle).
-			Width(cardWidth).
-			Padding(1, 2).
-			Render(inner)
+	desc := s.markdown(proj.Description, cardWidth-4, s.r.NewStyle().Foreground(s.theme.Text))
 
-		b.WriteString(card)
-		b.WriteString("\n")
+	var tags []string
+	for _, t := range proj.Tech {
+		tag := s.tag.Render(s.isolate(t))
+		if sk, ok := p.skillIndex(t); ok {
+			tag = markZone(zoneTag, sk, tag)
+		}
+		tags = append(tags, tag)
 	}
+	tagLine := strings.Join(tags, " ")
 
-	return b.String()
+	lines := []string{name, "", desc, "", tagLine}
+	if u := proj.link(); u != "" {
+		text := strings.TrimPrefix(u, "https://")
+		lines = append(lines, s.dimText.Render(s.g.Arrow+" ")+s.secondaryText.Render(hyperlink(u, s.isolate(text))))
+	}
+	inner := lipgloss.JoinVertical(lipgloss.Left, lines...)
+
+	card := s.r.NewStyle().
+		Border(s.g.Border).
+		BorderForeground(s.theme.Subtle).
+		Width(cardWidth).
+		Padding(1, 2)
+	if height > 0 {
+		card = card.Height(height - card.GetVerticalBorderSize())
+	}
+	return markZone(zoneCard, i, card.Render(inner))
 }
 
-func renderSkills(s styles, width int) string {
+func renderSkills(s styles, p portfolio, width, selected int) string {
+	if s.tier == tierWide {
+		return renderSkillColumns(s, p, width, selected)
+	}
+
 	var b strings.Builder
 	contentWidth := min(width-4, 72)
 
-	b.WriteString(s.sectionHeader.Render("Skills & Technologies"))
+	b.WriteString(s.sectionHeader.Render(s.l.T("Skills & Technologies")))
 	b.WriteString("\n\n")
 
-	categoryColors := []lipgloss.Color{yellow, green, pink, orange, secondary}
+	categoryColors := []lipgloss.Color{s.theme.Yellow, s.theme.Green, s.theme.Pink, s.theme.Orange, s.theme.Secondary}
 
-	for i, group := range skillGroups {
+	idx := 0
+	selectedSkill := ""
+	for i, group := range p.skillGroups {
 		color := categoryColors[i%len(categoryColors)]
-		header := s.r.NewStyle().Foreground(color).Bold(true).Render("■ " + group.Category)
+		header := s.r.NewStyle().Foreground(color).Bold(true).Render(s.g.Square + " " + group.Category)
 		b.WriteString(header)
 		b.WriteString("\n")
 
 		var tags []string
 		for _, sk := range group.Skills {
-			tags = append(tags, s.tag.Render(sk))
+			if idx == selected {
+				tags = append(tags, markZone(zoneTag, idx, s.selectedTag.Render(s.isolate(sk))))
+				selectedSkill = sk
+			} else {
+				tags = append(tags, markZone(zoneTag, idx, s.tag.Render(s.isolate(sk))))
+			}
+			idx++
 		}
 		b.WriteString("  " + strings.Join(tags, " "))
 		b.WriteString("\n")
 
-		if i < len(skillGroups)-1 {
-			b.WriteString(s.dimText.Render("  "+repeat("·", contentWidth-4)) + "\n")
+		if i < len(p.skillGroups)-1 {
+			b.WriteString(s.dimText.Render("  "+repeat(s.g.Dot, contentWidth-4)) + "\n")
 		}
 	}
 
-	return b.String()
+	if selectedSkill == "" {
+		return b.String()
+	}
+
+	list := b.String()
+	panelWidth := min(width-8, 48)
+	// Put the evidence beside the list when there is room, below it otherwise.
+	if width-lipgloss.Width(list)-4 >= panelWidth+4 {
+		panel := renderSkillEvidence(s, p, selectedSkill, panelWidth)
+		return lipgloss.JoinHorizontal(lipgloss.Top, list, "  ", panel) + "\n"
+	}
+	panel := renderSkillEvidence(s, p, selectedSkill, min(width-8, 68))
+	return list + "\n" + panel + "\n"
 }
 
-func renderEducation(s styles, width int) string {
+// renderSkillEvidence renders the side panel listing where a skill was used.
+func renderSkillEvidence(s styles, p portfolio, skill string, width int) string {
 	var b strings.Builder
-	contentWidth := min(width-4, 72)
+	ev := p.evidenceFor(skill)
 
-	b.WriteString(s.sectionHeader.Render("Education & Certifications"))
+	b.WriteString(s.accentText.Render(skill))
 	b.WriteString("\n\n")
 
-	for i, edu := range education {
-		b.WriteString(s.highlightText.Render("🎓 ") + s.accentText.Render(edu.Degree))
-		b.WriteString("\n")
-		b.WriteString("   " + s.secondaryText.Render(edu.Institution))
-		b.WriteString("  " + s.dimText.Render("("+edu.Period+")"))
-		b.WriteString("\n")
-		b.WriteString("   " + s.r.NewStyle().
-			Width(contentWidth-6).
-			Foreground(muted).
-			Render(edu.Details))
+	if ev.empty() {
+		b.WriteString(s.dimText.Render(s.l.T("No linked experience or projects yet.")))
+	}
+
+	if len(ev.Highlights) > 0 {
+		b.WriteString(s.secondaryText.Render(s.l.T("Experience")))
 		b.WriteString("\n")
+		company := ""
+		for _, ref := range ev.Highlights {
+			if ref.Experience.Company != company {
+				company = ref.Experience.Company
+				b.WriteString(s.greenText.Render(company) + " " + s.dimText.Render(ref.Experience.Title))
+				b.WriteString("\n")
+			}
+			text := s.r.NewStyle().
+				Width(width - 6).
+				Foreground(s.theme.Muted).
+				Render(ref.Highlight)
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, s.bullet.Render(s.g.Bullet+" "), text))
+			b.WriteString("\n")
+		}
+	}
 
-		if i < len(education)-1 {
+	if len(ev.Projects) > 0 {
+		if len(ev.Highlights) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(s.secondaryText.Render(s.l.T("Projects")))
+		b.WriteString("\n")
+		for _, proj := range ev.Projects {
+			b.WriteString(s.bullet.Render(s.g.Card+" ") + s.r.NewStyle().Foreground(s.theme.Text).Bold(true).Render(proj.Name))
 			b.WriteString("\n")
 		}
 	}
 
-	return b.String()
+	return s.r.NewStyle().
+		Border(s.g.Border).
+		BorderForeground(s.theme.Subtle).
+		Width(width).
+		Padding(0, 1).
+		Render(strings.TrimRight(b.String(), "\n"))
 }
 
-func renderContact(s styles, width int) string {
+func renderContact(s styles, contacts []ContactInfo, width int, formHint string) string {
 	var b strings.Builder
 
-	b.WriteString(s.sectionHeader.Render("Get In Touch"))
+	b.WriteString(s.sectionHeader.Render(s.l.T("Get In Touch")))
 	b.WriteString("\n\n")
 
 	intro := s.r.NewStyle().
 		Width(min(width-4, 72)).
-		Foreground(textColor).
-		Render("I'm always interested in hearing about new opportunities, collaborations, or just connecting with fellow engineers.")
+		Foreground(s.theme.Text).
+		Render(s.l.T("I'm always interested in hearing about new opportunities, collaborations, or just connecting with fellow engineers."))
 	b.WriteString(intro)
 	b.WriteString("\n\n")
-
-	iconMap := map[string]string{
-		"Email": "✉", "Phone": "☎", "Office": "☏",
-		"Portfolio": "⌂", "GitHub": "◆", "LinkedIn": "∞", "Location": "◉",
+	if formHint != "" {
+		b.WriteString(s.r.NewStyle().Width(min(width-4, 72)).Foreground(s.theme.Muted).Render(formHint))
+		b.WriteString("\n\n")
 	}
 
-	labelColors := []lipgloss.Color{pink, green, green, secondary, muted, accent, orange}
+	labelColors := []lipgloss.Color{s.theme.Pink, s.theme.Green, s.theme.Green, s.theme.Secondary, s.theme.Muted, s.theme.Accent, s.theme.Orange}
 
 	for i, c := range contacts {
-		icon := iconMap[c.Label]
-		if icon == "" {
-			icon = "→"
-		}
+		icon := s.g.contactIcon(c.Label)
 		color := labelColors[i%len(labelColors)]
 		styledIcon := s.r.NewStyle().Foreground(color).Bold(true).Render(icon)
-		styledLabel := s.r.NewStyle().Foreground(color).Bold(true).Width(12).Render(c.Label)
+		styledLabel := s.r.NewStyle().Foreground(color).Bold(true).Width(12).Render(s.l.T(c.Label))
 
 		if c.Label == "Portfolio" {
-			valuePart := s.r.NewStyle().Foreground(textColor).Bold(true).Render(
-				hyperlink("https://"+c.Value, c.Value),
+			valuePart := s.r.NewStyle().Foreground(s.theme.Text).Bold(true).Render(
+				hyperlink("https://"+c.Value, s.isolate(c.Value)),
 			)
-			httpsLink := s.r.NewStyle().Foreground(textColor).Bold(true).Render(
-				hyperlink("https://"+c.Value, "HTTPS"),
+			httpsLink := s.r.NewStyle().Foreground(s.theme.Text).Bold(true).Render(
+				hyperlink("https://"+c.Value, s.isolate("HTTPS")),
 			)
-			sshLink := s.r.NewStyle().Foreground(textColor).Bold(true).Render(
-				hyperlink("ssh://"+c.Value, "SSH"),
+			sshLink := s.r.NewStyle().Foreground(s.theme.Text).Bold(true).Render(
+				hyperlink("ssh://"+c.Value, s.isolate("SSH")),
 			)
-			suffix := s.dimText.Render(" (") + httpsLink + s.dimText.Render(" or ") + sshLink + s.dimText.Render(")") +
-				s.dimText.Render("\t// you're already here!")
-			b.WriteString(fmt.Sprintf("  %s  %s  %s%s\n", styledIcon, styledLabel, valuePart, suffix))
+			suffix := s.dimText.Render(" (") + httpsLink + s.dimText.Render(" "+s.l.T("or")+" ") + sshLink + s.dimText.Render(")") +
+				s.dimText.Render("    "+s.l.T("// you're already here!"))
+			b.WriteString(fmt.Sprintf("  %s  %s  %s%s\n", styledIcon, styledLabel, markZone(zoneContact, i, valuePart), suffix))
 		} else {
-			valueText := c.Value
+			valueText := s.isolate(c.Value)
 			if u := urlFor(c.Label, c.Value); u != "" {
-				valueText = hyperlink(u, c.Value)
+				valueText = hyperlink(u, valueText)
 			}
-			styledValue := s.r.NewStyle().Foreground(textColor).Bold(true).Render(valueText)
-			b.WriteString(fmt.Sprintf("  %s  %s  %s\n", styledIcon, styledLabel, styledValue))
+			styledValue := s.r.NewStyle().Foreground(s.theme.Text).Bold(true).Render(valueText)
+			b.WriteString(fmt.Sprintf("  %s  %s  %s\n", styledIcon, styledLabel, markZone(zoneContact, i, styledValue)))
 		}
 	}
 
 	b.WriteString("\n")
-	b.WriteString(s.dimText.Render("  Thanks for stopping by! ") + s.highlightText.Render("👋"))
+	b.WriteString(s.dimText.Render("  "+s.l.T("Thanks for stopping by!")+" ") + s.highlightText.Render(s.g.Wave))
 	b.WriteString("\n")
 
 	return b.String()
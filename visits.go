@@ -0,0 +1,126 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+)
+
+// Visitors who connect with a key are recognized when they come back: they
+// are welcomed, skip the intro and start on the tab they left from. Pressing
+// the Forget key deletes what is remembered about them and keeps it that way
+// until they press it again. Visitors who stay away longer than -retention
+// are forgotten.
+
+// lastTab is the tab a session is on and whether its visitor, by key
+// fingerprint, is to be remembered. The session's model keeps it up to date.
+type lastTab struct {
+	fingerprint string
+	title       string
+	remember    bool
+}
+
+// lastTabKey is the session context key of the session's *lastTab.
+type lastTabKey struct{}
+
+// saveLastTab saves the tab a remembered visitor's session ended on, once
+// its program has exited, so that their next visit can resume there.
+func (a *app) saveLastTab(next ssh.Handler) ssh.Handler {
+	return func(s ssh.Session) {
+		next(s)
+		last, ok := s.Context().Value(lastTabKey{}).(*lastTab)
+		if !ok || !last.remember || last.title == "" {
+			return
+		}
+		if err := a.store.saveTab(last.fingerprint, last.title); err != nil {
+			log.Printf("Could not save preferences: %v", err)
+		}
+	}
+}
+
+// pruneEvery is how often pruneStore looks for visitors to forget.
+const pruneEvery = 24 * time.Hour
+
+// pruneStore forgets visitors who haven't been back in keep, now and then
+// every pruneEvery, along with guestbook signing times that have run out.
+func pruneStore(st *store, keep time.Duration) {
+	for {
+		n, err := st.prune(time.Now().UTC(), keep, guestbookEvery)
+		if err != nil {
+			log.Printf("Could not prune database: %v", err)
+		} else if n > 0 {
+			log.Printf("Forgot %d visitors who hadn't been back in %v", n, keep)
+		}
+		time.Sleep(pruneEvery)
+	}
+}
+
+// returning reports whether the visitor has been here before.
+func (v visitor) returning() bool {
+	return v.remember && v.prefs.Visits > 1
+}
+
+// resumeTab is the tab a returning visitor left from, or the first tab.
+func (m model) resumeTab() int {
+	if !m.visitor.returning() {
+		return 0
+	}
+	for i, t := range m.tabs {
+		if t.Title == m.visitor.prefs.Tab {
+			return i
+		}
+	}
+	return 0
+}
+
+// welcome greets a returning visitor, or is "" for a new one.
+func (m model) welcome() string {
+	v := m.visitor
+	if !v.returning() {
+		return ""
+	}
+	if m.activeTab > 0 {
+		return m.locale.F("Welcome back! Visit #%d, picking up on %s.", v.prefs.Visits, m.locale.T(m.tabs[m.activeTab].Title))
+	}
+	return m.locale.F("Welcome back! Visit #%d.", v.prefs.Visits)
+}
+
+// toggleRemember forgets the visitor, deleting their saved preferences and
+// visits, or starts remembering them again. Their current settings last
+// until the session ends either way.
+func (m model) toggleRemember() (model, tea.Cmd) {
+	v := &m.visitor
+	if v.fingerprint == "" || v.store == nil {
+		return m, nil
+	}
+	st, fp := v.store, v.fingerprint
+	var save func() error
+	var notice string
+	if v.remember {
+		v.remember = false
+		save = func() error { return st.forget(fp) }
+		notice = m.locale.F("Forgot you. Press %s to be remembered again.", m.keys.Forget.Help().Key)
+	} else {
+		v.remember = true
+		v.prefs.Visits = 1
+		v.prefs.LastVisit = time.Now().UTC()
+		v.prefs.Tab = m.tabs[m.activeTab].Title
+		p := v.prefs
+		save = func() error { return st.remember(fp, p) }
+		notice = m.locale.T("You'll be remembered from now on.")
+	}
+	m.syncKeys()
+	cmd := func() tea.Msg {
+		if err := save(); err != nil {
+			log.Printf("Could not save preferences: %v", err)
+		}
+		return nil
+	}
+	if m.accessible {
+		return m, tea.Batch(tea.Println("\n"+notice), cmd)
+	}
+	m, clear := m.setNotice(notice)
+	return m, tea.Batch(clear, cmd)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The wide layout spreads tabs across the screen instead of keeping them to
+// one readable column: Experience lists roles beside the selected one,
+// Projects is a grid of cards and Skills sets its groups side by side.
+
+// renderExperienceWide lists the roles on the left and shows the selected
+// one in full on the right.
+func renderExperienceWide(s styles, p portfolio, width, selected int) string {
+	const listWidth = 44
+	detailWidth := min(width-4-listWidth-4, 100)
+
+	exps := p.sortedExperiences()
+	var entries []string
+	for i, exp := range exps {
+		marker, title := "  ", s.mutedText.Bold(true)
+		if i == selected {
+			marker, title = s.accentText.Render(s.g.Bullet+" "), s.accentText
+		}
+		entry := lipgloss.JoinVertical(lipgloss.Left,
+			marker+title.Render(truncate(exp.Title, listWidth-2, s.g.Ellipsis)),
+			"  "+s.secondaryText.Render(truncate(exp.Company, listWidth-2, s.g.Ellipsis)),
+			"  "+s.dimText.Render(s.l.words(exp.PeriodText())),
+		)
+		// Padding every line to the list width makes the whole entry
+		// clickable, not just its text.
+		entry = s.r.NewStyle().Width(listWidth).Render(entry)
+		entries = append(entries, markZone(zoneRole, i, entry))
+	}
+	list := strings.Join(entries, "\n\n")
+
+	var detail string
+	if selected >= 0 && selected < len(exps) {
+		detail = renderRoleDetail(s, exps[selected], detailWidth)
+	}
+
+	return s.sectionHeader.Render(s.l.T("Work Experience")) + "\n\n" +
+		lipgloss.JoinHorizontal(lipgloss.Top, list, "    ", detail) + "\n"
+}
+
+// renderRoleDetail renders one role with its description and highlights in
+// a box width cells wide.
+func renderRoleDetail(s styles, exp Experience, width int) string {
+	var b strings.Builder
+	inner := width - 4
+
+	b.WriteString(s.accentText.Render(exp.Title) + "\n")
+	period := s.dimText.Render(s.l.words(exp.PeriodText()))
+	if t := exp.Tenure(); t != "" {
+		period += s.dimText.Render("  " + s.g.Dot + "  " + s.l.words(t))
+	}
+	b.WriteString(s.secondaryText.Render(exp.Company) + "  " + period + "\n\n")
+	b.WriteString(s.markdown(exp.Description, inner, s.r.NewStyle().Foreground(s.theme.Text).Italic(true)))
+	b.WriteString("\n\n")
+
+	for _, h := range exp.Highlights {
+		text := s.r.NewStyle().
+			Width(inner - 2).
+			Foreground(s.theme.Muted).
+			Render(h)
+		b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, s.bullet.Render(s.g.Bullet+" "), text))
+		b.WriteString("\n")
+	}
+
+	return s.r.NewStyle().
+		Border(s.g.Border).
+		BorderForeground(s.theme.Subtle).
+		Width(width).
+		Padding(1, 2).
+		Render(strings.TrimRight(b.String(), "\n"))
+}
+
+// renderProjectGrid lays the project cards out in as many columns as fit,
+// with the cards in each row made the same height.
+func renderProjectGrid(s styles, p portfolio, width int, heading string, projects []Project) string {
+	const cardWidth, gap = 56, 2
+	cols := max((width-4+gap)/(cardWidth+2+gap), 1) // cards have a border either side
+
+	var rows []string
+	for start := 0; start < len(projects); start += cols {
+		end := min(start+cols, len(projects))
+		height := 0
+		for i := start; i < end; i++ {
+			height = max(height, lipgloss.Height(renderProjectCard(s, p, i, projects[i], cardWidth, 0)))
+		}
+		var cards []string
+		for i := start; i < end; i++ {
+			if i > start {
+				cards = append(cards, strings.Repeat(" ", gap))
+			}
+			cards = append(cards, renderProjectCard(s, p, i, projects[i], cardWidth, height))
+		}
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, cards...))
+	}
+
+	return s.sectionHeader.Render(heading) + "\n\n" + strings.Join(rows, "\n") + "\n"
+}
+
+// renderSkillColumns sets each skill group out as a column, one skill per
+// line, with the selected skill's evidence beside them.
+func renderSkillColumns(s styles, p portfolio, width, selected int) string {
+	const panelWidth = 56
+
+	colWidth := 0
+	for _, sk := range p.allSkills() {
+		colWidth = max(colWidth, lipgloss.Width(s.tag.Render(sk))+4)
+	}
+	for _, g := range p.skillGroups {
+		colWidth = max(colWidth, lipgloss.Width(s.g.Square+" "+g.Category)+2)
+	}
+	cols := max((width-4-panelWidth-4)/colWidth, 1)
+
+	categoryColors := []lipgloss.Color{s.theme.Yellow, s.theme.Green, s.theme.Pink, s.theme.Orange, s.theme.Secondary}
+
+	idx := 0
+	selectedSkill := ""
+	var columns []string
+	for i, group := range p.skillGroups {
+		color := categoryColors[i%len(categoryColors)]
+		lines := []string{s.r.NewStyle().Foreground(color).Bold(true).Render(s.g.Square + " " + group.Category)}
+		for _, sk := range group.Skills {
+			tag := s.tag
+			if idx == selected {
+				tag = s.selectedTag
+				selectedSkill = sk
+			}
+			lines = append(lines, "  "+markZone(zoneTag, idx, tag.Render(sk)))
+			idx++
+		}
+		columns = append(columns, s.r.NewStyle().Width(colWidth).Render(strings.Join(lines, "\n")))
+	}
+
+	var rows []string
+	for start := 0; start < len(columns); start += cols {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, columns[start:min(start+cols, len(columns))]...))
+	}
+	grid := strings.Join(rows, "\n\n")
+
+	if selectedSkill != "" {
+		grid = lipgloss.JoinHorizontal(lipgloss.Top, grid, "    ", renderSkillEvidence(s, p, selectedSkill, panelWidth))
+	}
+	return s.sectionHeader.Render(s.l.T("Skills & Technologies")) + "\n\n" + grid + "\n"
+}
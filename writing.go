@@ -0,0 +1,203 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The Writing tab lists the posts in the posts directory and opens them in a
+// reader. It is only shown while the directory has posts; sessions look for
+// new, changed and removed files every postsRefresh.
+
+const writingTab = "Writing"
+
+const postsRefresh = 5 * time.Second
+
+// postsMsg carries a fresh listing of the posts directory.
+type postsMsg []Post
+
+// watchPosts lists the posts directory again after postsRefresh.
+func (m model) watchPosts() tea.Cmd {
+	if m.app.posts == nil {
+		return nil
+	}
+	lib := m.app.posts
+	return tea.Tick(postsRefresh, func(time.Time) tea.Msg { return postsMsg(lib.list()) })
+}
+
+// tabsFor is the tab bar for a set of posts: Writing is added after the
+// other tabs when there are any.
+func tabsFor(tabs []Tab, posts []Post) []Tab {
+	if len(posts) == 0 {
+		return tabs
+	}
+	return append(append([]Tab(nil), tabs...), Tab{Title: writingTab, Type: sectionPosts})
+}
+
+// setPosts replaces the posts, showing or hiding the Writing tab and keeping
+// the cursor, filter and open post where they still make sense.
+func (m model) setPosts(posts []Post) model {
+	if reflect.DeepEqual(posts, m.posts) {
+		return m
+	}
+	m.posts = posts
+	if m.postTag != "" && len(filterPosts(posts, m.postTag)) == 0 {
+		m.postTag = ""
+	}
+	if n := len(m.filteredPosts()); m.postCursor >= n {
+		m.postCursor = max(n-1, 0)
+		m.reading = false
+	}
+	return m.setTabs()
+}
+
+// filteredPosts is the posts shown under the current tag filter.
+func (m model) filteredPosts() []Post {
+	return filterPosts(m.posts, m.postTag)
+}
+
+// setPostTag filters the list to tag, or shows every post for "".
+func (m model) setPostTag(tag string) model {
+	m.postTag = tag
+	m.postCursor = 0
+	m.setContent()
+	m.viewport.GotoTop()
+	return m
+}
+
+// nextPostTag is the tag after the current filter, wrapping through "" for
+// all posts.
+func (m model) nextPostTag() string {
+	tags := append([]string{""}, postTags(m.posts)...)
+	for i, t := range tags {
+		if t == m.postTag {
+			return tags[(i+1)%len(tags)]
+		}
+	}
+	return ""
+}
+
+// setReading opens the selected post in the reader, or closes it.
+func (m model) setReading(reading bool) model {
+	m.reading = reading && len(m.filteredPosts()) > 0
+	m.syncKeys()
+	m.setContent()
+	m.viewport.GotoTop()
+	return m
+}
+
+// postPage is the reader's position as "Page x of y".
+func (m model) postPage() string {
+	height := max(m.viewport.Height, 1)
+	pages := max((m.viewport.TotalLineCount()+height-1)/height, 1)
+	page := min(m.viewport.YOffset/height+1, pages)
+	if m.viewport.AtBottom() {
+		page = pages
+	}
+	return m.locale.F("Page %d of %d", page, pages)
+}
+
+// renderWriting lists posts with the selected one marked, under a row of
+// tags to filter by.
+func renderWriting(s styles, width int, posts []Post, tag string, selected int) string {
+	var b strings.Builder
+	contentWidth := min(width-4, 72)
+
+	b.WriteString(s.sectionHeader.Render(s.l.T(writingTab)))
+	b.WriteString("\n\n")
+
+	if tags := postTags(posts); len(tags) > 0 {
+		var chips []string
+		for i, t := range append([]string{""}, tags...) {
+			label := t
+			if t == "" {
+				label = s.l.T("all")
+			}
+			chip := s.tag.Render(s.isolate(label))
+			if t == tag {
+				chip = s.selectedTag.Render(s.isolate(label))
+			}
+			chips = append(chips, markZone(zonePostTag, i, chip))
+		}
+		b.WriteString(s.r.NewStyle().Width(contentWidth).Render(strings.Join(chips, " ")))
+		b.WriteString("\n\n")
+	}
+
+	var entries []string
+	for i, p := range filterPosts(posts, tag) {
+		marker, title := "  ", s.mutedText.Bold(true)
+		if i == selected {
+			marker, title = s.accentText.Render(s.g.Bullet+" "), s.accentText
+		}
+		meta := s.l.words(p.Date.Format("Jan 2, 2006"))
+		if len(p.Tags) > 0 {
+			meta += "  " + s.g.Dot + "  " + strings.Join(p.Tags, ", ")
+		}
+		lines := []string{
+			marker + title.Render(truncate(p.Title, contentWidth-2, s.g.Ellipsis)),
+			"  " + s.dimText.Render(truncate(meta, contentWidth-2, s.g.Ellipsis)),
+		}
+		if ex := p.excerpt(); ex != "" {
+			lines = append(lines, "  "+s.r.NewStyle().Foreground(s.theme.Text).Render(truncate(ex, contentWidth-2, s.g.Ellipsis)))
+		}
+		entry := s.r.NewStyle().Width(contentWidth).Render(strings.Join(lines, "\n"))
+		entries = append(entries, markZone(zonePost, i, entry))
+	}
+	b.WriteString(strings.Join(entries, "\n\n"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// renderPost renders a post in full for the reader.
+func renderPost(s styles, width int, p Post) string {
+	var b strings.Builder
+	contentWidth := min(width-4, 72)
+
+	b.WriteString(s.accentText.Render(p.Title))
+	b.WriteString("\n")
+	meta := s.dimText.Render(s.l.words(p.Date.Format("January 2, 2006")))
+	if len(p.Tags) > 0 {
+		var tags []string
+		for _, t := range p.Tags {
+			tags = append(tags, s.tag.Render(s.isolate(t)))
+		}
+		meta += "  " + strings.Join(tags, " ")
+	}
+	b.WriteString(s.r.NewStyle().Width(contentWidth).Render(meta))
+	b.WriteString("\n\n")
+	b.WriteString(s.markdown(p.Body, contentWidth, s.r.NewStyle().Foreground(s.theme.Text)))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// plainWriting lists the posts for accessible mode, saying which key reads
+// them in full.
+func plainWriting(l *locale, posts []Post, nextKey string) string {
+	var b strings.Builder
+	for i, p := range posts {
+		b.WriteString(l.F("Post %d of %d: %s", i+1, len(posts), p.Title) + "\n")
+		b.WriteString(l.F("Published: %s", l.words(p.Date.Format("January 2, 2006"))) + "\n")
+		if len(p.Tags) > 0 {
+			b.WriteString(l.F("Tags: %s", strings.Join(p.Tags, ", ")) + "\n")
+		}
+		if ex := p.excerpt(); ex != "" {
+			b.WriteString(ex + "\n")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(l.F("Press %s to read each post in full.", nextKey) + "\n")
+	return b.String()
+}
+
+// plainPost reads out post i in full.
+func plainPost(l *locale, posts []Post, i int) string {
+	p := posts[i]
+	var b strings.Builder
+	b.WriteString("\n" + l.F("Post %d of %d: %s", i+1, len(posts), p.Title) + "\n")
+	b.WriteString(l.F("Published: %s", l.words(p.Date.Format("January 2, 2006"))) + "\n\n")
+	b.WriteString(plainMarkdown(p.Body) + "\n")
+	return b.String()
+}